@@ -43,6 +43,14 @@ func GetAccounts(guildID string, filter interface{}, sortBy interface{}, limit i
 	return readAccounts(guildID, filter, sortBy, limit)
 }
 
+// GetAccountPage returns a page of accounts that match the filter, skipping the first `skip` accounts.
+func GetAccountPage(guildID string, filter interface{}, sortBy interface{}, skip int64, limit int64) []*Account {
+	log.Trace("--> bank.Bank.GetAccountPage")
+	defer log.Trace("<-- bank.Bank.GetAccountPage")
+
+	return readAccountPage(guildID, filter, sortBy, skip, limit)
+}
+
 // Deposit adds the amount to the balance of the account.
 func (account *Account) Deposit(amt int) error {
 	log.Trace("--> bank.Account.Deposit")
@@ -102,10 +102,21 @@ func TestResetMonthlyBalances(t *testing.T) {
 	account.MonthlyBalance = 750
 	writeAccount(account)
 
-	ResetMonthlyBalances()
+	ResetMonthlyBalances("67890")
 
 	account = GetAccount(bank.GuildID, "54321")
 	if account.MonthlyBalance != 0 {
 		t.Errorf("Expected monthly balance to be 0, got %d", account.MonthlyBalance)
 	}
+
+	// Accounts for excluded guilds aren't reset
+	account.MonthlyBalance = 750
+	writeAccount(account)
+
+	ResetMonthlyBalances(bank.GuildID)
+
+	account = GetAccount(bank.GuildID, "54321")
+	if account.MonthlyBalance != 750 {
+		t.Errorf("Expected monthly balance to be 750, got %d", account.MonthlyBalance)
+	}
 }
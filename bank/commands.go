@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/text/language"
@@ -12,20 +13,15 @@ import (
 )
 
 var (
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"bank-admin": bankAdmin,
-		"bank":       bank,
-	}
-
-	adminCommands = []*discordgo.ApplicationCommand{
-		{
+	adminRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "bank-admin",
 			Description: "Commands used to interact with the economy for this server.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "account",
 					Description: "Sets the amount of credits for a given member.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     setAccountBalance,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -44,7 +40,7 @@ var (
 				{
 					Name:        "balance",
 					Description: "Set the default balance for the bank for the server.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     setDefaultBalance,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -57,7 +53,7 @@ var (
 				{
 					Name:        "name",
 					Description: "Set the name of the bank for the server.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     setBankName,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -70,7 +66,7 @@ var (
 				{
 					Name:        "currency",
 					Description: "Set the currency for the server.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     setBankCurrency,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -83,71 +79,27 @@ var (
 				{
 					Name:        "info",
 					Description: "Get information about the banking system configuration.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     getBankInfo,
 				},
 			},
 		},
-	}
+	)
 
-	memberCommands = []*discordgo.ApplicationCommand{
-		{
+	memberRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "bank",
 			Description: "Commands used to interact with the economy for this server.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "account",
 					Description: "Bank account balance for the member.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     account,
 				},
 			},
 		},
-	}
+	)
 )
 
-// bankAdmin routes the bankAdmin commands to the proper handers.
-func bankAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> bank.bankAdmin")
-	defer log.Trace("<-- bank.bankAdmin")
-
-	p := discmsg.GetPrinter(language.AmericanEnglish)
-
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "balance":
-		setDefaultBalance(s, i)
-	case "name":
-		setBankName(s, i)
-	case "currency":
-		setBankCurrency(s, i)
-	case "account":
-		setAccountBalance(s, i)
-	case "info":
-		getBankInfo(s, i)
-	default:
-		log.WithFields(log.Fields{"command": options[0].Name}).Warn("unknown bank-admin command")
-	}
-}
-
-// bank routes the bank commands to the proper handlers.
-func bank(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> bank.bank")
-	defer log.Trace("<-- bank.bank")
-
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "account":
-		account(s, i)
-	default:
-		log.WithFields(log.Fields{"command": options[0].Name}).Warn("unknown bank command")
-	}
-}
-
 // account gets information about the member's bank account.
 func account(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> bank.account")
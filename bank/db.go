@@ -9,14 +9,24 @@ import (
 const (
 	BANK_COLLECTION    = "banks"
 	ACCOUNT_COLLECTION = "bank_accounts"
+	LEDGER_COLLECTION  = "bank_ledger"
 )
 
-// Resets the monthly balances for all accounts in all banks.
-func ResetMonthlyBalances() {
+// ResetMonthlyBalances resets the monthly balances for all accounts in all banks, other than those for
+// the excluded guilds.
+func ResetMonthlyBalances(excludedGuildIDs ...string) {
 	log.Trace("--> bank.ResetMonthlyBalances")
 	defer log.Trace("<-- bank.ResetMonthlyBalances")
 
-	filter := bson.M{}
+	if excludedGuildIDs == nil {
+		// A nil slice is saved as null, which MongoDB doesn't accept for `$nin`
+		excludedGuildIDs = []string{}
+	}
+	filter := bson.M{"guild_id": bson.M{"$nin": excludedGuildIDs}}
+	if count, _ := db.Count(ACCOUNT_COLLECTION, filter); count == 0 {
+		// UpdateMany upserts, so avoid creating an empty account when there are none to reset
+		return
+	}
 	update := bson.M{"monthly_balance": 0}
 	err := db.UpdateMany(ACCOUNT_COLLECTION, filter, update)
 	if err != nil {
@@ -24,6 +34,23 @@ func ResetMonthlyBalances() {
 	}
 }
 
+// ResetGuildMonthlyBalances resets the monthly balances for all accounts in the guild's bank.
+func ResetGuildMonthlyBalances(guildID string) {
+	log.Trace("--> bank.ResetGuildMonthlyBalances")
+	defer log.Trace("<-- bank.ResetGuildMonthlyBalances")
+
+	filter := bson.M{"guild_id": guildID}
+	if count, _ := db.Count(ACCOUNT_COLLECTION, filter); count == 0 {
+		// UpdateMany upserts, so avoid creating an empty account for a guild without any
+		return
+	}
+	update := bson.M{"monthly_balance": 0}
+	err := db.UpdateMany(ACCOUNT_COLLECTION, filter, update)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to reset monthly balances for the guild")
+	}
+}
+
 // readBank gets the bank from the database and returns the value, if it exists, or returns nil if the
 // bank does not exist in the database.
 func readBank(guildID string) *Bank {
@@ -73,6 +100,23 @@ func readAccounts(guildID string, filter interface{}, sortBy interface{}, limit
 	return accounts
 }
 
+// readAccountPage reads a page of accounts for a guild from the database, skipping the first `skip` accounts
+// that match the filter.
+func readAccountPage(guildID string, filter interface{}, sortBy interface{}, skip int64, limit int64) []*Account {
+	log.Trace("--> bank.readAccountPage")
+	defer log.Trace("<-- bank.readAccountPage")
+
+	var accounts []*Account
+	err := db.FindPage(ACCOUNT_COLLECTION, filter, &accounts, sortBy, skip, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "skip": skip, "error": err}).Error("unable to read a page of accounts from the database")
+		return nil
+	}
+	log.WithFields(log.Fields{"guild": guildID, "skip": skip, "count": len(accounts)}).Debug("read a page of accounts from the database")
+
+	return accounts
+}
+
 // readAccount reads the account from the database and returns the value, if it exists, or returns nil if the
 // account does not exist in the database
 func readAccount(guildID string, memberID string) *Account {
@@ -111,3 +155,38 @@ func writeAccount(account *Account) error {
 
 	return nil
 }
+
+// readLedgerEntries reads the most recent ledger entries for the member, newest first.
+func readLedgerEntries(guildID string, memberID string, limit int64) []*LedgerEntry {
+	log.Trace("--> bank.readLedgerEntries")
+	defer log.Trace("<-- bank.readLedgerEntries")
+
+	var entries []*LedgerEntry
+	filter := bson.M{"guild_id": guildID, "member_id": memberID}
+	sort := bson.D{{Key: "timestamp", Value: -1}}
+	err := db.FindMany(LEDGER_COLLECTION, filter, &entries, sort, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "member": memberID, "error": err}).Error("unable to read ledger entries from the database")
+		return nil
+	}
+	log.WithFields(log.Fields{"guild": guildID, "member": memberID, "count": len(entries)}).Debug("read ledger entries from the database")
+
+	return entries
+}
+
+// writeLedgerEntry adds the entry to the ledger in the database.
+func writeLedgerEntry(entry *LedgerEntry) error {
+	log.Trace("--> bank.writeLedgerEntry")
+	defer log.Trace("<-- bank.writeLedgerEntry")
+
+	entry.ID = primitive.NewObjectID()
+	filter := bson.D{{Key: "_id", Value: entry.ID}}
+	err := db.UpdateOrInsert(LEDGER_COLLECTION, filter, entry)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": entry.GuildID, "member": entry.MemberID, "error": err}).Error("unable to save the ledger entry to the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": entry.GuildID, "member": entry.MemberID, "amount": entry.Amount, "reason": entry.Reason}).Debug("save ledger entry to the database")
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package bank
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A LedgerEntry records a single change to the balance of an account, along with the reason
+// for the change. Positive amounts are deposits and negative amounts are withdrawals.
+type LedgerEntry struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string             `json:"guild_id" bson:"guild_id"`
+	MemberID  string             `json:"member_id" bson:"member_id"`
+	Amount    int                `json:"amount" bson:"amount"`
+	Balance   int                `json:"balance" bson:"balance"`
+	Reason    string             `json:"reason" bson:"reason"`
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+}
+
+// DepositWithReason adds the amount to the balance of the account, and records the deposit
+// in the ledger.
+func (account *Account) DepositWithReason(amt int, reason string) error {
+	log.Trace("--> bank.Account.DepositWithReason")
+	defer log.Trace("<-- bank.Account.DepositWithReason")
+
+	err := account.Deposit(amt)
+	if err != nil {
+		return err
+	}
+	addLedgerEntry(account, amt, reason)
+
+	return nil
+}
+
+// WithdrawWithReason deducts the amount from the balance of the account, and records the
+// withdrawal in the ledger.
+func (account *Account) WithdrawWithReason(amt int, reason string) error {
+	log.Trace("--> bank.Account.WithdrawWithReason")
+	defer log.Trace("<-- bank.Account.WithdrawWithReason")
+
+	err := account.Withdraw(amt)
+	if err != nil {
+		return err
+	}
+	addLedgerEntry(account, -amt, reason)
+
+	return nil
+}
+
+// GetLedger returns the most recent ledger entries for the account, newest first.
+func (account *Account) GetLedger(limit int64) []*LedgerEntry {
+	log.Trace("--> bank.Account.GetLedger")
+	defer log.Trace("<-- bank.Account.GetLedger")
+
+	return readLedgerEntries(account.GuildID, account.MemberID, limit)
+}
+
+// addLedgerEntry records a change to the balance of the account in the ledger.
+func addLedgerEntry(account *Account, amt int, reason string) {
+	entry := &LedgerEntry{
+		GuildID:   account.GuildID,
+		MemberID:  account.MemberID,
+		Amount:    amt,
+		Balance:   account.CurrentBalance,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	writeLedgerEntry(entry)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/game/heist"
+	"github.com/rbrabson/goblin/game/race"
 	"github.com/rbrabson/goblin/leaderboard"
 	"github.com/rbrabson/goblin/payday"
 	"github.com/rbrabson/goblin/role"
@@ -59,7 +60,7 @@ func main() {
 	leaderboard.Start()
 	payday.Start()
 	role.Start()
-	// race.Start()
+	race.Start()
 
 	bot := discord.NewBot(BotName, Version, Revision)
 	err = bot.Session.Open()
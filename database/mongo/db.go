@@ -138,6 +138,43 @@ func (m *MongoDB) FindMany(collectionName string, filter interface{}, data inter
 	return nil
 }
 
+// FindPage reads a single page of documents from the database that match the filter. The
+// documents are sorted before skipping the first `skip` documents and returning up to `limit`
+// documents.
+func (m *MongoDB) FindPage(collectionName string, filter interface{}, data interface{}, sortBy interface{}, skip int64, limit int64) error {
+	log.Trace("--> mongo.FindPage")
+	defer log.Trace("<-- mongoDB.FindPage")
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_TIMEOUT)
+	defer cancel()
+
+	collection, err := m.getCollection(ctx, collectionName)
+	if err != nil {
+		return err
+	}
+
+	findOptions := options.Find()
+	findOptions.Sort = sortBy
+	findOptions.SetSkip(skip)
+	findOptions.SetLimit(limit)
+
+	cur, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.WithFields(log.Fields{"database": m.dbname, "collection": collectionName, "filter": filter, "error": err}).Debug("unable to find the document")
+		return err
+	}
+	defer func() {
+		cur.Close(ctx)
+	}()
+	err = cur.All(ctx, data)
+	if err != nil {
+		log.WithFields(log.Fields{"database": m.dbname, "collection": collectionName, "filter": filter, "error": err}).Error("unable to decode the documents")
+		return ErrInvalidDocument
+	}
+
+	return nil
+}
+
 // FindOne loads a document identified by documentID from the collection into data.
 func (m *MongoDB) FindOne(collectionName string, filter interface{}, data interface{}) error {
 	log.Trace("--> mongoDB.FindOne")
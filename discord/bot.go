@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"maps"
 	"os"
 
 	"github.com/bwmarrin/discordgo"
@@ -30,6 +31,7 @@ type Bot struct {
 	DB      mongo.MongoDB
 	appID   string
 	guildID string
+	dryRun  bool
 	timer   chan int
 }
 
@@ -58,6 +60,7 @@ func NewBot(botName string, version string, revision string) *Bot {
 		timer:   make(chan int),
 		appID:   appID,
 		guildID: guildID,
+		dryRun:  GetenvBool("DISCORD_DRY_RUN_SLASH_COMMANDS"),
 	}
 	bot.Session.Identify.Intents = botIntents
 
@@ -72,24 +75,33 @@ func NewBot(botName string, version string, revision string) *Bot {
 		log.WithFields(log.Fields{"plugin": plugin.GetName()}).Info("initialized plugin")
 	}
 
-	componentHandlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate))
-	commandHandlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate))
+	componentHandlers := make(map[string]HandlerFunc)
+	commandHandlers := make(map[string]HandlerFunc)
 	commands := make([]*discordgo.ApplicationCommand, 0, 2)
 
 	// Add commands and handlers for the bot itself
-	commands = append(commands, helpCommands...)
-	for key, value := range helpCommandHandler {
-		commandHandlers[key] = value
+	for _, router := range []*Router{helpRouter, adminHelpRouter} {
+		commands = append(commands, router.Commands()...)
+		for key, handler := range router.Handlers() {
+			commandHandlers[key] = Chain(handler, commandMiddleware...)
+		}
 	}
+	addCommands(commands)
+	maps.Copy(commandPermissions, helpRouter.Permissions(guild.PermissionMember))
+	maps.Copy(commandPermissions, adminHelpRouter.Permissions(guild.PermissionAdmin))
 
 	// Add commands and handlers for each plugin
 	for _, plugin := range ListPlugin() {
 		commands = append(commands, plugin.GetCommands()...)
+		addCommands(plugin.GetCommands())
+		maps.Copy(commandPermissions, plugin.GetCommandPermissions())
 		for key, handler := range plugin.GetCommandHandlers() {
-			commandHandlers[key] = handler
+			commandHandlers[key] = Chain(handler, commandMiddleware...)
+			handlerPlugins[key] = plugin.GetName()
 		}
 		for key, handler := range plugin.GetComponentHandlers() {
-			componentHandlers[key] = handler
+			componentHandlers[key] = Chain(handler, componentMiddleware...)
+			handlerPlugins[key] = plugin.GetName()
 		}
 	}
 
@@ -104,11 +116,17 @@ func NewBot(botName string, version string, revision string) *Bot {
 				log.WithField("command", i.ApplicationCommandData().Name).Warn("unhandled command")
 			}
 		case discordgo.InteractionMessageComponent:
-			if h, ok := componentHandlers[i.MessageComponentData().CustomID]; ok {
+			if h, ok := componentHandlers[getHandlerName(i)]; ok {
 				h(s, i)
 			} else {
 				log.WithField("component", i.MessageComponentData().CustomID).Warn("unhandled component")
 			}
+		case discordgo.InteractionModalSubmit:
+			if h, ok := componentHandlers[getHandlerName(i)]; ok {
+				h(s, i)
+			} else {
+				log.WithField("modal", i.ModalSubmitData().CustomID).Warn("unhandled modal")
+			}
 		}
 	})
 	log.Debug("bot handlers added")
@@ -128,18 +146,10 @@ func (bot *Bot) DeleteCommands() {
 	log.Trace("--> discord.Bot.DeleteCommands")
 	defer log.Trace("<-- discord.Bot.DeleteCommands")
 
-	// Delete all bot commands indivdually
-	// commands, err := bot.Session.ApplicationCommands(bot.appID, bot.guildID)
-	// if err != nil {
-	// 	log.WithFields(log.Fields{"appID": bot.appID, "guildID": bot.guildID, "error": err}).Fatal("failed to get bot commands")
-	// }
-	// for _, command := range commands {
-	// 	log.WithFields(log.Fields{"name": command.Name, "description": command.Description}).Debug("deleting command")
-	// 	err := bot.Session.ApplicationCommandDelete(bot.appID, bot.guildID, command.ID)
-	// 	if err != nil {
-	// 		log.WithFields(log.Fields{"name": command.Name, "description": command.Description, "error": err}).Error("failed to delete command")
-	// 	}
-	// }
+	if bot.dryRun {
+		log.Info("dry run, not deleting old bot commands")
+		return
+	}
 
 	log.Debug("deleting old bot commands")
 	_, err := bot.Session.ApplicationCommandBulkOverwrite(bot.appID, bot.guildID, nil)
@@ -149,20 +159,48 @@ func (bot *Bot) DeleteCommands() {
 	log.Debug("old bot commands deleted")
 }
 
-// LoadCommands register all the commands. This implicitly will call the function added above that
-// adds the command and component handlers for each plugin.
+// LoadCommands registers the commands. The commands already registered with Discord are compared
+// against the commands, and only those that are new, have changed or are no longer defined are
+// created, updated or deleted. This keeps the bot well within the daily limit on creating commands
+// when it is restarted. In a dry run, the changes are logged but not made.
 func (bot *Bot) LoadCommands(commands []*discordgo.ApplicationCommand) {
 	log.Trace("--> discord.Bot.LoadCommands")
 	defer log.Trace("<-- discord.Bot.LoadCommands")
 
 	log.WithFields(log.Fields{"appID": bot.appID, "guildID": bot.guildID}).Debug("load new bot commands")
-	_, err := bot.Session.ApplicationCommandBulkOverwrite(bot.appID, bot.guildID, commands)
+	registered, err := bot.Session.ApplicationCommands(bot.appID, bot.guildID)
 	if err != nil {
-		for _, command := range commands {
-			log.WithFields(log.Fields{"name": command.Name, "description": command.Description}).Error("failed to load command")
-		}
-		log.WithFields(log.Fields{"appID": bot.appID, "guildID": bot.guildID, "commands": commands, "error": err}).Fatal("failed to load bot commands")
+		log.WithFields(log.Fields{"appID": bot.appID, "guildID": bot.guildID, "error": err}).Fatal("failed to get registered bot commands")
+	}
 
+	changes := diffCommands(registered, commands)
+	if changes.isEmpty() {
+		log.Info("bot commands are up to date")
+		return
+	}
+	logCommandChanges(changes)
+	if bot.dryRun {
+		log.Info("dry run, not loading new bot commands")
+		return
+	}
+
+	for _, command := range changes.create {
+		_, err := bot.Session.ApplicationCommandCreate(bot.appID, bot.guildID, command)
+		if err != nil {
+			log.WithFields(log.Fields{"name": command.Name, "description": command.Description, "error": err}).Error("failed to create command")
+		}
+	}
+	for _, command := range changes.update {
+		_, err := bot.Session.ApplicationCommandEdit(bot.appID, bot.guildID, command.ID, command)
+		if err != nil {
+			log.WithFields(log.Fields{"name": command.Name, "description": command.Description, "error": err}).Error("failed to update command")
+		}
+	}
+	for _, command := range changes.delete {
+		err := bot.Session.ApplicationCommandDelete(bot.appID, bot.guildID, command.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"name": command.Name, "description": command.Description, "error": err}).Error("failed to delete command")
+		}
 	}
-	log.Info("new bot commands loaded")
+	log.WithFields(log.Fields{"created": len(changes.create), "updated": len(changes.update), "deleted": len(changes.delete)}).Info("new bot commands loaded")
 }
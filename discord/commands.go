@@ -5,34 +5,32 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/text/language"
 
 	"github.com/rbrabson/goblin/guild"
 	"github.com/rbrabson/goblin/internal/discmsg"
 )
 
 var (
-	helpCommandHandler = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"help":      help,
-		"adminhelp": adminHelp,
-		"version":   version,
-	}
-
-	helpCommands = []*discordgo.ApplicationCommand{
-		{
-
+	helpRouter = NewRouter(
+		&Command{
 			Name:        "help",
 			Description: "Provides a description of commands for this server.",
+			Handler:     help,
 		},
-		{
+	)
+
+	adminHelpRouter = NewRouter(
+		&Command{
 			Name:        "adminhelp",
 			Description: "Provides a description of admin commands for this server.",
+			Handler:     adminHelp,
 		},
-		{
+		&Command{
 			Name:        "version",
 			Description: "Returns the version of heist running on the server.",
+			Handler:     version,
 		},
-	}
+	)
 )
 
 // help sends a help message for plugin commands.
@@ -40,7 +38,7 @@ func help(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> help")
 	log.Trace("<-- help")
 
-	discmsg.SendEphemeralResponse(s, i, getHelp())
+	discmsg.SendEphemeralResponse(s, i, getHelp(i.GuildID))
 }
 
 // adminHelp sends a help message for administrative commands.
@@ -48,14 +46,7 @@ func adminHelp(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> adminHelp")
 	log.Trace("<-- adminHelp")
 
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		p := discmsg.GetPrinter(language.AmericanEnglish)
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
-	discmsg.SendEphemeralResponse(s, i, getAdminHelp())
+	discmsg.SendEphemeralResponse(s, i, getAdminHelp(i.GuildID))
 }
 
 // version shows the version of bot you are running.
@@ -63,24 +54,20 @@ func version(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> version")
 	defer log.Trace("<-- version")
 
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		p := discmsg.GetPrinter(language.AmericanEnglish)
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
 	discmsg.SendEphemeralResponse(s, i, "You are running "+BotName+" version "+Version+"-"+Revision+".")
 }
 
-// getHelp gets help about commands from all plugins.
-func getHelp() string {
+// getHelp gets help about commands from all plugins enabled for the guild.
+func getHelp(guildID string) string {
 	log.Trace("--> discord.getMemberHelp")
 	log.Trace("<-- discord.getMemberHelp")
 
 	var sb strings.Builder
 	log.WithFields(log.Fields{"plugins": ListPlugin()}).Debug("plugins")
 	for _, plugin := range ListPlugin() {
+		if !guild.IsPluginEnabled(guildID, plugin.GetName()) {
+			continue
+		}
 		log.WithFields(log.Fields{"plugin": plugin.GetName()}).Debug("plugin")
 		for _, str := range plugin.GetHelp() {
 			sb.WriteString(str)
@@ -90,13 +77,16 @@ func getHelp() string {
 	return sb.String()
 }
 
-// getAdminHelp returns help about administrative commands for all bots.
-func getAdminHelp() string {
+// getAdminHelp returns help about administrative commands for all plugins enabled for the guild.
+func getAdminHelp(guildID string) string {
 	log.Trace("--> discord.getAdminHelp")
 	log.Trace("<-- discord.getAdminHelp")
 
 	var sb strings.Builder
 	for _, plugin := range ListPlugin() {
+		if !guild.IsPluginEnabled(guildID, plugin.GetName()) {
+			continue
+		}
 		for _, str := range plugin.GetAdminHelp() {
 			sb.WriteString(str)
 		}
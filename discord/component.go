@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CUSTOM_ID_SEPARATOR  = ":" // Separates the prefix of a custom ID from the arguments that follow it
+	MAX_CUSTOM_ID_LENGTH = 100 // Maximum length Discord allows for a custom ID
+)
+
+// CustomID returns the custom ID for a component or modal, made up of the prefix used to route it to a
+// handler and the arguments passed to the handler, such as `race_bet:<raceID>:<memberID>`. Neither the
+// prefix nor the arguments may contain the separator.
+func CustomID(prefix string, args ...string) string {
+	customID := strings.Join(append([]string{prefix}, args...), CUSTOM_ID_SEPARATOR)
+	if len(customID) > MAX_CUSTOM_ID_LENGTH {
+		log.WithFields(log.Fields{"customID": customID, "length": len(customID)}).Error("custom ID is too long")
+	}
+	return customID
+}
+
+// ParseCustomID returns the prefix used to route the custom ID to a handler, and the arguments encoded
+// in the custom ID.
+func ParseCustomID(customID string) (string, []string) {
+	parts := strings.Split(customID, CUSTOM_ID_SEPARATOR)
+	return parts[0], parts[1:]
+}
+
+// getCustomID returns the custom ID of the component or modal for the interaction.
+func getCustomID(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		return i.ModalSubmitData().CustomID
+	default:
+		return ""
+	}
+}
+
+// ComponentArgs returns the arguments encoded in the custom ID of the component or modal for the
+// interaction.
+func ComponentArgs(i *discordgo.InteractionCreate) []string {
+	_, args := ParseCustomID(getCustomID(i))
+	return args
+}
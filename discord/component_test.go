@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCustomID(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		args     []string
+		customID string
+	}{
+		{"join_race", nil, "join_race"},
+		{"race_bet", []string{"1700000000000", "12345"}, "race_bet:1700000000000:12345"},
+		{"lb_next", []string{"balance", "2"}, "lb_next:balance:2"},
+	}
+
+	for _, test := range tests {
+		customID := CustomID(test.prefix, test.args...)
+		if customID != test.customID {
+			t.Errorf("expected custom ID %q, got %q", test.customID, customID)
+		}
+		prefix, args := ParseCustomID(customID)
+		if prefix != test.prefix {
+			t.Errorf("expected prefix %q, got %q", test.prefix, prefix)
+		}
+		if !slices.Equal(args, test.args) {
+			t.Errorf("expected args %v, got %v", test.args, args)
+		}
+	}
+}
+
+func TestComponentArgs(t *testing.T) {
+	i := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{CustomID: "heist_lb_next:stolen:3"},
+		},
+	}
+	if name := getHandlerName(i); name != "heist_lb_next" {
+		t.Errorf("expected handler name %q, got %q", "heist_lb_next", name)
+	}
+	args := ComponentArgs(i)
+	if !slices.Equal(args, []string{"stolen", "3"}) {
+		t.Errorf("expected args %v, got %v", []string{"stolen", "3"}, args)
+	}
+}
@@ -0,0 +1,162 @@
+package discord
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+)
+
+const (
+	COMMAND_COOLDOWN  = 1 * time.Second // Minimum time between uses of the same command by a member
+	RESPONSE_DEADLINE = 3 * time.Second // Time Discord allows for responding to an interaction
+)
+
+// HandlerFunc handles a command, component or modal interaction.
+type HandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate)
+
+// Middleware wraps a handler, running before and/or after it. A middleware may stop the interaction
+// from reaching the handler by returning without calling the next handler.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+var (
+	// commandMiddleware is run, in order, before each command handler
+	commandMiddleware = []Middleware{recoverPanic, timeHandler, loadGuildContext, requirePlugin, requirePermission, cooldown}
+	// componentMiddleware is run, in order, before each component and modal handler
+	componentMiddleware = []Middleware{recoverPanic, timeHandler, loadGuildContext, requirePlugin}
+
+	cooldowns    = make(map[string]time.Time)
+	cooldownLock = sync.Mutex{}
+)
+
+// Chain wraps the handler with the middleware. The first middleware is the outermost, and so runs first.
+func Chain(handler HandlerFunc, middleware ...Middleware) HandlerFunc {
+	for idx := len(middleware) - 1; idx >= 0; idx-- {
+		handler = middleware[idx](handler)
+	}
+	return handler
+}
+
+// getHandlerName returns the name used to register the handler for the interaction, which is the name
+// of the command or the prefix of the custom ID of the component or modal.
+func getHandlerName(i *discordgo.InteractionCreate) string {
+	if i.Type == discordgo.InteractionApplicationCommand {
+		return i.ApplicationCommandData().Name
+	}
+	prefix, _ := ParseCustomID(getCustomID(i))
+	return prefix
+}
+
+// recoverPanic recovers from a panic in the handler so that a single failing interaction doesn't stop
+// the bot, and lets the member know that their request failed.
+func recoverPanic(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(log.Fields{"guild": i.GuildID, "handler": getHandlerName(i), "panic": r, "stack": string(debug.Stack())}).Error("recovered from panic in handler")
+				p := discmsg.GetPrinter(language.AmericanEnglish)
+				discmsg.SendEphemeralResponse(s, i, p.Sprintf("Something went wrong while handling your request. Please try again later."))
+			}
+		}()
+		next(s, i)
+	}
+}
+
+// timeHandler logs how long the handler takes, warning when it takes longer than Discord allows for
+// a response.
+func timeHandler(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		start := time.Now()
+		next(s, i)
+		elapsed := time.Since(start)
+
+		fields := log.Fields{"guild": i.GuildID, "handler": getHandlerName(i), "elapsed": elapsed}
+		if elapsed > RESPONSE_DEADLINE {
+			log.WithFields(fields).Warn("slow handler")
+		} else {
+			log.WithFields(fields).Debug("handled interaction")
+		}
+	}
+}
+
+// loadGuildContext makes sure the interaction came from a member of a guild, and keeps the member's
+// name up to date so that handlers don't need to.
+func loadGuildContext(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+			p := discmsg.GetPrinter(language.AmericanEnglish)
+			discmsg.SendEphemeralResponse(s, i, p.Sprintf("This command can only be used in a server."))
+			return
+		}
+		guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.DisplayName())
+		next(s, i)
+	}
+}
+
+// requirePlugin stops the interaction if the plugin that handles it is disabled for the guild.
+func requirePlugin(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handler := getHandlerName(i)
+		plugin, ok := handlerPlugins[handler]
+		if ok && !guild.IsPluginEnabled(i.GuildID, plugin) {
+			log.WithFields(log.Fields{"guild": i.GuildID, "plugin": plugin, "handler": handler}).Debug("plugin is disabled")
+			p := discmsg.GetPrinter(language.AmericanEnglish)
+			discmsg.SendEphemeralResponse(s, i, p.Sprintf("The %s plugin is disabled on this server.", plugin))
+			return
+		}
+		next(s, i)
+	}
+}
+
+// requirePermission stops the command if the member doesn't have permission to use it.
+func requirePermission(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if !canUseCommand(s, i) {
+			p := discmsg.GetPrinter(language.AmericanEnglish)
+			discmsg.SendEphemeralResponse(s, i, p.Sprintf("You do not have permission to use this command."))
+			return
+		}
+		next(s, i)
+	}
+}
+
+// cooldown stops a member from using the same command again until the cooldown has passed.
+func cooldown(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		key := i.GuildID + ":" + i.Member.User.ID + ":" + getCommandPath(i.ApplicationCommandData())
+		if remaining := checkCooldown(key, time.Now()); remaining > 0 {
+			p := discmsg.GetPrinter(language.AmericanEnglish)
+			discmsg.SendEphemeralResponse(s, i, p.Sprintf("You are using this command too quickly. Try again in %s.", remaining.Round(100*time.Millisecond)))
+			return
+		}
+		next(s, i)
+	}
+}
+
+// checkCooldown returns how much longer the cooldown for the key has to run, or zero if the key isn't
+// on cooldown, in which case a new cooldown is started.
+func checkCooldown(key string, now time.Time) time.Duration {
+	cooldownLock.Lock()
+	defer cooldownLock.Unlock()
+
+	if until, ok := cooldowns[key]; ok && until.After(now) {
+		return until.Sub(now)
+	}
+	cooldowns[key] = now.Add(COMMAND_COOLDOWN)
+
+	// Remove expired cooldowns so the map doesn't grow without bound
+	if len(cooldowns) > 1000 {
+		for k, until := range cooldowns {
+			if !until.After(now) {
+				delete(cooldowns, k)
+			}
+		}
+	}
+
+	return 0
+}
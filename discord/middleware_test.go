@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestChain(t *testing.T) {
+	calls := make([]string, 0, 5)
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+				calls = append(calls, name+" before")
+				next(s, i)
+				calls = append(calls, name+" after")
+			}
+		}
+	}
+	stop := func(next HandlerFunc) HandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) {}
+	}
+	handler := func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		calls = append(calls, "handler")
+	}
+
+	Chain(handler, record("outer"), record("inner"))(nil, nil)
+	expected := []string{"outer before", "inner before", "handler", "inner after", "outer after"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+
+	calls = calls[:0]
+	Chain(handler, record("outer"), stop, record("inner"))(nil, nil)
+	expected = []string{"outer before", "outer after"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestCheckCooldown(t *testing.T) {
+	now := time.Now()
+	key := "guild:member:heist start"
+
+	if remaining := checkCooldown(key, now); remaining != 0 {
+		t.Errorf("expected no cooldown on first use, got %s", remaining)
+	}
+	if remaining := checkCooldown(key, now.Add(COMMAND_COOLDOWN/2)); remaining != COMMAND_COOLDOWN/2 {
+		t.Errorf("expected %s remaining, got %s", COMMAND_COOLDOWN/2, remaining)
+	}
+	if remaining := checkCooldown("guild:member:race start", now); remaining != 0 {
+		t.Errorf("expected no cooldown for a different command, got %s", remaining)
+	}
+	if remaining := checkCooldown(key, now.Add(COMMAND_COOLDOWN)); remaining != 0 {
+		t.Errorf("expected the cooldown to have expired, got %s", remaining)
+	}
+}
@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/guild"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	commandPermissions = make(map[string]guild.Permission)
+	commandPaths       = make(map[string]bool)
+	handlerPlugins     = make(map[string]string) // Name of the plugin for each command and component handler
+)
+
+// addCommands records the full path of each command, subcommand group and subcommand so that
+// overrides may be validated.
+func addCommands(commands []*discordgo.ApplicationCommand) {
+	for _, command := range commands {
+		commandPaths[command.Name] = true
+		addCommandPaths(command.Name, command.Options)
+	}
+}
+
+// addCommandPaths records the path of each subcommand group and subcommand within the options.
+func addCommandPaths(parent string, options []*discordgo.ApplicationCommandOption) {
+	for _, option := range options {
+		if option.Type != discordgo.ApplicationCommandOptionSubCommandGroup && option.Type != discordgo.ApplicationCommandOptionSubCommand {
+			continue
+		}
+		path := parent + " " + option.Name
+		commandPaths[path] = true
+		addCommandPaths(path, option.Options)
+	}
+}
+
+// IsCommand returns whether the path, such as "heist-admin clear", is a command, subcommand group or
+// subcommand registered by the bot.
+func IsCommand(path string) bool {
+	return commandPaths[guild.NormalizeCommand(path)]
+}
+
+// getCommandPath returns the full path of the command being invoked, including any subcommand group
+// and subcommand.
+func getCommandPath(data discordgo.ApplicationCommandInteractionData) string {
+	path := data.Name
+	options := data.Options
+	for len(options) > 0 {
+		option := options[0]
+		if option.Type != discordgo.ApplicationCommandOptionSubCommandGroup && option.Type != discordgo.ApplicationCommandOptionSubCommand {
+			break
+		}
+		path += " " + option.Name
+		options = option.Options
+	}
+	return path
+}
+
+// canUseCommand returns whether the member invoking the interaction may use the command, taking into
+// account any overrides set for the guild.
+func canUseCommand(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	log.Trace("--> discord.canUseCommand")
+	defer log.Trace("<-- discord.canUseCommand")
+
+	path := getCommandPath(i.ApplicationCommandData())
+	return guild.CanUseCommand(s, i.GuildID, i.Member.User.ID, path, getCommandPermission(path))
+}
+
+// getCommandPermission returns the permission required to use the command. If no permission was
+// recorded for the command, the permission for the closest command it is a part of is used.
+func getCommandPermission(path string) guild.Permission {
+	for {
+		if permission, ok := commandPermissions[path]; ok {
+			return permission
+		}
+		idx := strings.LastIndex(path, " ")
+		if idx == -1 {
+			return guild.PermissionMember
+		}
+		path = path[:idx]
+	}
+}
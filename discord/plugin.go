@@ -5,6 +5,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
+	"github.com/rbrabson/goblin/guild"
 )
 
 var (
@@ -17,6 +18,7 @@ type Plugin interface {
 	Initialize(bot *Bot, db *mongo.MongoDB)
 	GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate)
 	GetCommands() []*discordgo.ApplicationCommand
+	GetCommandPermissions() map[string]guild.Permission
 	GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate)
 	GetHelp() []string
 	GetName() string
@@ -28,6 +30,16 @@ func ListPlugin() []Plugin {
 	return plugins
 }
 
+// GetPlugin returns the plugin with the given name, or `nil` if no such plugin has been registered
+func GetPlugin(name string) Plugin {
+	for _, plugin := range plugins {
+		if plugin.GetName() == name {
+			return plugin
+		}
+	}
+	return nil
+}
+
 // RegisterPlugin registers the plugin to be used within the bot
 func RegisterPlugin(plugin Plugin) {
 	mutex.Lock()
@@ -0,0 +1,150 @@
+package discord
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// commandChanges are the changes needed to bring the slash commands registered with Discord in line
+// with the commands defined by the bot and its plugins.
+type commandChanges struct {
+	create []*discordgo.ApplicationCommand // Commands that aren't registered yet
+	update []*discordgo.ApplicationCommand // Commands whose definition has changed, with the ID of the registered command
+	delete []*discordgo.ApplicationCommand // Registered commands that are no longer defined
+}
+
+// isEmpty returns `true` if no commands need to be created, updated or deleted.
+func (c *commandChanges) isEmpty() bool {
+	return len(c.create) == 0 && len(c.update) == 0 && len(c.delete) == 0
+}
+
+// diffCommands compares the commands registered with Discord against the commands defined by the bot,
+// matching them by name, and returns the changes needed to register the defined commands.
+func diffCommands(registered []*discordgo.ApplicationCommand, commands []*discordgo.ApplicationCommand) *commandChanges {
+	changes := &commandChanges{}
+
+	registeredByName := make(map[string]*discordgo.ApplicationCommand, len(registered))
+	for _, command := range registered {
+		registeredByName[command.Name] = command
+	}
+
+	defined := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		defined[command.Name] = true
+		current, ok := registeredByName[command.Name]
+		switch {
+		case !ok:
+			changes.create = append(changes.create, command)
+		case !sameCommand(current, command):
+			update := *command
+			update.ID = current.ID
+			changes.update = append(changes.update, &update)
+		}
+	}
+
+	for _, command := range registered {
+		if !defined[command.Name] {
+			changes.delete = append(changes.delete, command)
+		}
+	}
+
+	return changes
+}
+
+// sameCommand returns `true` if the registered command has the same definition as the command. Fields
+// that are filled in by Discord, such as the ID and version, are ignored.
+func sameCommand(registered *discordgo.ApplicationCommand, command *discordgo.ApplicationCommand) bool {
+	return reflect.DeepEqual(commandDefinition(registered), commandDefinition(command))
+}
+
+// commandDefinition returns the parts of the command that are defined by the bot, in a form that may be
+// compared regardless of whether the command was defined locally or read back from Discord.
+func commandDefinition(command *discordgo.ApplicationCommand) any {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+	definition := map[string]any{
+		"type":                       commandType,
+		"name":                       command.Name,
+		"description":                command.Description,
+		"name_localizations":         command.NameLocalizations,
+		"description_localizations":  command.DescriptionLocalizations,
+		"default_member_permissions": command.DefaultMemberPermissions,
+		"options":                    command.Options,
+	}
+
+	// Round trip through JSON so the numbers in choices have the same type, then drop values that
+	// Discord omits, such as options that aren't required
+	data, err := json.Marshal(definition)
+	if err != nil {
+		log.WithFields(log.Fields{"command": command.Name, "error": err}).Error("unable to marshal the command")
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		log.WithFields(log.Fields{"command": command.Name, "error": err}).Error("unable to unmarshal the command")
+		return nil
+	}
+	return dropEmpty(value)
+}
+
+// dropEmpty returns the value with all empty values removed from the maps and slices within it.
+func dropEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, elem := range v {
+			elem = dropEmpty(elem)
+			if !isEmptyValue(elem) {
+				result[key] = elem
+			}
+		}
+		return result
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, elem := range v {
+			result = append(result, dropEmpty(elem))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// isEmptyValue returns `true` if the value is the zero value for a JSON type.
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// logCommandChanges logs the changes that are needed to register the commands.
+func logCommandChanges(changes *commandChanges) {
+	for _, command := range changes.create {
+		log.WithFields(log.Fields{"name": command.Name, "description": command.Description}).Info("create command")
+	}
+	for _, command := range changes.update {
+		definition, _ := json.Marshal(command)
+		log.WithFields(log.Fields{"name": command.Name, "id": command.ID, "definition": string(definition)}).Info("update command")
+	}
+	for _, command := range changes.delete {
+		log.WithFields(log.Fields{"name": command.Name, "id": command.ID}).Info("delete command")
+	}
+}
@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestDiffCommands(t *testing.T) {
+	commands := []*discordgo.ApplicationCommand{
+		{
+			Name:        "race",
+			Description: "Race commands.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "start",
+					Description: "Starts a race.",
+				},
+			},
+		},
+		{
+			Name:        "heist",
+			Description: "Heist commands.",
+		},
+		{
+			Name:        "help",
+			Description: "Provides a description of commands for this server.",
+		},
+	}
+
+	// Commands read back from Discord have their IDs and defaults filled in
+	var registered []*discordgo.ApplicationCommand
+	err := json.Unmarshal([]byte(`[
+		{"id": "1", "application_id": "10", "version": "100", "type": 1, "name": "race", "description": "Race commands.", "dm_permission": true,
+			"options": [{"type": 1, "name": "start", "description": "Starts a race.", "options": []}]},
+		{"id": "2", "application_id": "10", "version": "100", "type": 1, "name": "heist", "description": "Old heist commands."},
+		{"id": "3", "application_id": "10", "version": "100", "type": 1, "name": "shop", "description": "Shop commands."}
+	]`), &registered)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the registered commands: %v", err)
+	}
+
+	changes := diffCommands(registered, commands)
+	if len(changes.create) != 1 || changes.create[0].Name != "help" {
+		t.Errorf("expected to create the help command, got %v", changes.create)
+	}
+	if len(changes.update) != 1 || changes.update[0].Name != "heist" || changes.update[0].ID != "2" {
+		t.Errorf("expected to update the heist command with ID 2, got %v", changes.update)
+	}
+	if len(changes.delete) != 1 || changes.delete[0].Name != "shop" {
+		t.Errorf("expected to delete the shop command, got %v", changes.delete)
+	}
+	if commands[1].ID != "" {
+		t.Errorf("expected the defined command to be unchanged, got ID %q", commands[1].ID)
+	}
+
+	if changes := diffCommands(registered[:1], commands[:1]); !changes.isEmpty() {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestSameCommandChoices(t *testing.T) {
+	command := &discordgo.ApplicationCommand{
+		Name:        "bet",
+		Description: "Bet on a race.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "amount",
+				Description: "The amount to bet.",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Small", Value: 100},
+					{Name: "Large", Value: 1000},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		t.Fatalf("unable to marshal the command: %v", err)
+	}
+	registered := &discordgo.ApplicationCommand{}
+	if err := json.Unmarshal(data, registered); err != nil {
+		t.Fatalf("unable to unmarshal the command: %v", err)
+	}
+	if !sameCommand(registered, command) {
+		t.Error("expected the registered command to match the command")
+	}
+
+	registered.Options[0].Required = false
+	if sameCommand(registered, command) {
+		t.Error("expected a change to an option to be detected")
+	}
+}
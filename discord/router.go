@@ -0,0 +1,160 @@
+package discord
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+)
+
+// Command is a slash command, or a subcommand group or subcommand within one. A command either has a
+// handler that is called when the command is used, or subcommands that the command is routed to.
+//
+// By default, a command requires the same permission as the command it is part of, or the permission
+// of the router for a top-level command. Setting the permission, such as to `guild.PermissionModerator`
+// for a subcommand of an admin command, changes the permission required for the command and any
+// subcommands within it. The zero value, `guild.PermissionMember`, keeps the default.
+type Command struct {
+	Name        string
+	Description string
+	Options     []*discordgo.ApplicationCommandOption // Options passed to the handler
+	Handler     HandlerFunc                           // Called when the command is used
+	Subcommands []*Command                            // Subcommands, or subcommands within a group
+	Permission  guild.Permission                      // Permission required to use the command, if not the default
+}
+
+// Router declares a set of slash commands and routes each command to the handler for the subcommand
+// being used. The command definitions registered with Discord, the command handlers and the help for
+// the commands are all generated from the same declaration.
+type Router struct {
+	commands []*Command
+}
+
+// NewRouter returns a router for the commands.
+func NewRouter(commands ...*Command) *Router {
+	return &Router{commands: commands}
+}
+
+// Commands returns the definitions of the commands to register with Discord.
+func (r *Router) Commands() []*discordgo.ApplicationCommand {
+	commands := make([]*discordgo.ApplicationCommand, 0, len(r.commands))
+	for _, command := range r.commands {
+		commands = append(commands, &discordgo.ApplicationCommand{
+			Name:        command.Name,
+			Description: command.Description,
+			Options:     command.options(),
+		})
+	}
+	return commands
+}
+
+// Handlers returns the handler for each command, which routes the command to the handler for the
+// subcommand being used.
+func (r *Router) Handlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
+	handlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate), len(r.commands))
+	for _, command := range r.commands {
+		handlers[command.Name] = command.handle
+	}
+	return handlers
+}
+
+// Permissions returns the permission required to use each command, subcommand group and subcommand,
+// keyed by the path of the command, such as "race-admin reset". Commands that don't set a permission
+// require the given permission.
+func (r *Router) Permissions(permission guild.Permission) map[string]guild.Permission {
+	permissions := make(map[string]guild.Permission)
+	for _, command := range r.commands {
+		command.addPermissions(permissions, "", permission)
+	}
+	return permissions
+}
+
+// Help returns a line of help for each command without subcommands, and for each subcommand or
+// subcommand group of the other commands, sorted by the name of the command.
+func (r *Router) Help() []string {
+	help := make([]string, 0, len(r.commands))
+	for _, command := range r.commands {
+		if len(command.Subcommands) == 0 {
+			help = append(help, fmt.Sprintf("- **/%s**:  %s\n", command.Name, command.Description))
+			continue
+		}
+		for _, subcommand := range command.Subcommands {
+			help = append(help, fmt.Sprintf("- **/%s %s**:  %s\n", command.Name, subcommand.Name, subcommand.Description))
+		}
+	}
+	slices.Sort(help)
+	return help
+}
+
+// options returns the options for the command, which are either the options passed to its handler or
+// the definitions of its subcommands.
+func (c *Command) options() []*discordgo.ApplicationCommandOption {
+	if len(c.Subcommands) == 0 {
+		return c.Options
+	}
+
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(c.Subcommands))
+	for _, subcommand := range c.Subcommands {
+		optionType := discordgo.ApplicationCommandOptionSubCommand
+		if len(subcommand.Subcommands) != 0 {
+			optionType = discordgo.ApplicationCommandOptionSubCommandGroup
+		}
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Name:        subcommand.Name,
+			Description: subcommand.Description,
+			Type:        optionType,
+			Options:     subcommand.options(),
+		})
+	}
+	return options
+}
+
+// addPermissions adds the permission required to use the command, and each of its subcommands, to the
+// permissions. The command requires the given permission unless it sets its own.
+func (c *Command) addPermissions(permissions map[string]guild.Permission, parent string, permission guild.Permission) {
+	path := c.Name
+	if parent != "" {
+		path = parent + " " + c.Name
+	}
+	if c.Permission != guild.PermissionMember {
+		permission = c.Permission
+	}
+	permissions[path] = permission
+	for _, subcommand := range c.Subcommands {
+		subcommand.addPermissions(permissions, path, permission)
+	}
+}
+
+// handle routes the command to the handler for the subcommand being used.
+func (c *Command) handle(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> discord.Command.handle")
+	defer log.Trace("<-- discord.Command.handle")
+
+	command := c.route(i.ApplicationCommandData().Options)
+	if command == nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "command": getCommandPath(i.ApplicationCommandData())}).Warn("unknown command")
+		discmsg.SendEphemeralResponse(s, i, "Command is unknown")
+		return
+	}
+	command.Handler(s, i)
+}
+
+// route returns the command or subcommand whose handler should be called for the options, or `nil` if
+// there isn't one.
+func (c *Command) route(options []*discordgo.ApplicationCommandInteractionDataOption) *Command {
+	if c.Handler != nil {
+		return c
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	for _, subcommand := range c.Subcommands {
+		if subcommand.Name == options[0].Name {
+			return subcommand.route(options[0].Options)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package discord
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/guild"
+)
+
+func TestRouter(t *testing.T) {
+	var called string
+	handler := func(name string) HandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) { called = name }
+	}
+	router := NewRouter(
+		&Command{
+			Name:        "game",
+			Description: "Game commands.",
+			Subcommands: []*Command{
+				{
+					Name:        "start",
+					Description: "Starts a game.",
+					Handler:     handler("start"),
+				},
+				{
+					Name:        "config",
+					Description: "Configures the game.",
+					Subcommands: []*Command{
+						{
+							Name:        "wait",
+							Description: "Sets the wait time.",
+							Handler:     handler("config wait"),
+							Options: []*discordgo.ApplicationCommandOption{
+								{Type: discordgo.ApplicationCommandOptionInteger, Name: "time", Description: "The wait time."},
+							},
+						},
+					},
+				},
+			},
+		},
+		&Command{
+			Name:        "ping",
+			Description: "Pings the bot.",
+			Handler:     handler("ping"),
+		},
+	)
+
+	commands := router.Commands()
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(commands))
+	}
+	game := commands[0]
+	if game.Options[0].Type != discordgo.ApplicationCommandOptionSubCommand {
+		t.Errorf("expected `start` to be a subcommand, got %v", game.Options[0].Type)
+	}
+	if game.Options[1].Type != discordgo.ApplicationCommandOptionSubCommandGroup {
+		t.Errorf("expected `config` to be a subcommand group, got %v", game.Options[1].Type)
+	}
+	if wait := game.Options[1].Options[0]; wait.Type != discordgo.ApplicationCommandOptionSubCommand || wait.Options[0].Name != "time" {
+		t.Errorf("expected `config wait` to be a subcommand with a `time` option, got %+v", wait)
+	}
+
+	handlers := router.Handlers()
+	tests := []struct {
+		command  string
+		options  []*discordgo.ApplicationCommandInteractionDataOption
+		expected string
+	}{
+		{"game", []*discordgo.ApplicationCommandInteractionDataOption{{Name: "start", Type: discordgo.ApplicationCommandOptionSubCommand}}, "start"},
+		{"game", []*discordgo.ApplicationCommandInteractionDataOption{{Name: "config", Type: discordgo.ApplicationCommandOptionSubCommandGroup, Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: "wait", Type: discordgo.ApplicationCommandOptionSubCommand},
+		}}}, "config wait"},
+		{"ping", nil, "ping"},
+	}
+	for _, tc := range tests {
+		called = ""
+		i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: tc.command, Options: tc.options},
+		}}
+		handlers[tc.command](nil, i)
+		if called != tc.expected {
+			t.Errorf("expected %q to be called, got %q", tc.expected, called)
+		}
+	}
+
+	expected := []string{
+		"- **/game config**:  Configures the game.\n",
+		"- **/game start**:  Starts a game.\n",
+		"- **/ping**:  Pings the bot.\n",
+	}
+	if help := router.Help(); !slices.Equal(help, expected) {
+		t.Errorf("expected help %q, got %q", expected, help)
+	}
+}
+
+func TestRouterPermissions(t *testing.T) {
+	router := NewRouter(
+		&Command{
+			Name:        "game-admin",
+			Description: "Game admin commands.",
+			Subcommands: []*Command{
+				{
+					Name:        "reset",
+					Description: "Resets the game.",
+					Permission:  guild.PermissionModerator,
+				},
+				{
+					Name:        "config",
+					Description: "Configures the game.",
+					Subcommands: []*Command{
+						{Name: "wait", Description: "Sets the wait time."},
+					},
+				},
+			},
+		},
+	)
+
+	permissions := router.Permissions(guild.PermissionAdmin)
+	expected := map[string]guild.Permission{
+		"game-admin":             guild.PermissionAdmin,
+		"game-admin reset":       guild.PermissionModerator,
+		"game-admin config":      guild.PermissionAdmin,
+		"game-admin config wait": guild.PermissionAdmin,
+	}
+	if !maps.Equal(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+
+	maps.Copy(commandPermissions, permissions)
+	defer func() {
+		for path := range permissions {
+			delete(commandPermissions, path)
+		}
+	}()
+	if permission := getCommandPermission("game-admin reset"); permission != guild.PermissionModerator {
+		t.Errorf("expected the moderator permission, got %s", permission)
+	}
+	if permission := getCommandPermission("game-admin unknown"); permission != guild.PermissionAdmin {
+		t.Errorf("expected the admin permission, got %s", permission)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
 	"github.com/rbrabson/goblin/internal/channel"
 	"github.com/rbrabson/goblin/internal/discmsg"
@@ -19,23 +20,38 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// Limits used when validating the heist configuration.
+var (
+	minCrewSize     = 1.0
+	noCrewLimit     = 0.0
+	minEscapedShare = 1.0
+	maxEscapedShare = 10.0
+	minLootPercent  = 1.0
+	maxLootPercent  = 100.0
+	minHeat         = 0.0
+	minHeatDecay    = 1.0
+
+	minLeaderboardPage = 1.0
+)
+
 // componentHandlers are the buttons that appear on messages sent by this bot.
 var (
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"heist":       heist,
-		"heist-admin": heistAdmin,
-		"join_heist":  joinHeist,
+	componentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+		"join_heist":        joinHeist,
+		"heist_lb_previous": heistLeaderboardPrevious,
+		"heist_lb_next":     heistLeaderboardNext,
 	}
 
-	adminCommands = []*discordgo.ApplicationCommand{
-		{
+	adminRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "heist-admin",
 			Description: "Heist admin commands.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "clear",
 					Description: "Clears the criminal settings for the user.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     clearMember,
+					Permission:  guild.PermissionModerator,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -48,17 +64,16 @@ var (
 				{
 					Name:        "config",
 					Description: "Configures the Heist bot.",
-					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
-					Options: []*discordgo.ApplicationCommandOption{
+					Subcommands: []*discord.Command{
 						{
 							Name:        "info",
 							Description: "Returns the configuration information for the server.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configInfo,
 						},
 						{
 							Name:        "bail",
 							Description: "Sets the base cost of bail.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configBail,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
@@ -68,10 +83,23 @@ var (
 								},
 							},
 						},
+						{
+							Name:        "bonus",
+							Description: "Sets the success bonus based on the size of the crew.",
+							Handler:     configBonus,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "rates",
+									Description: "Percent of the target's crew size and bonus, such as `20:0,40:1,60:3,80:4,100:5`.",
+									Required:    true,
+								},
+							},
+						},
 						{
 							Name:        "cost",
 							Description: "Sets the cost to plan or join a heist.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configCost,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
@@ -81,10 +109,31 @@ var (
 								},
 							},
 						},
+						{
+							Name:        "crew",
+							Description: "Sets the minimum and maximum size of a crew.",
+							Handler:     configCrew,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The minimum number of members required to start a heist.",
+									Required:    true,
+									MinValue:    &minCrewSize,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum number of members that may join a heist, or 0 for no limit.",
+									Required:    false,
+									MinValue:    &noCrewLimit,
+								},
+							},
+						},
 						{
 							Name:        "death",
 							Description: "Sets how long players remain dead.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configDeath,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
@@ -95,22 +144,76 @@ var (
 							},
 						},
 						{
-							Name:        "patrol",
-							Description: "Sets the time the authorities will prevent a new heist.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "escaped",
+							Description: "Sets how many shares of the loot those who escape receive.",
+							Handler:     configEscaped,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
-									Name:        "time",
-									Description: "The time the authorities will patrol, in seconds.",
+									Name:        "shares",
+									Description: "The number of shares received by those who escape. Those apprehended receive one share.",
 									Required:    true,
+									MinValue:    &minEscapedShare,
+									MaxValue:    maxEscapedShare,
+								},
+							},
+						},
+						{
+							Name:        "heat",
+							Description: "Sets how much heat the authorities feel from heists, and how fast it cools off.",
+							Handler:     configHeat,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "heist",
+									Description: "The heat added by each heist.",
+									Required:    false,
+									MinValue:    &minHeat,
+									MaxValue:    MAX_HEAT,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "success",
+									Description: "The heat added for each member who escapes.",
+									Required:    false,
+									MinValue:    &minHeat,
+									MaxValue:    MAX_HEAT,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "decay",
+									Description: "The heat that cools off each hour.",
+									Required:    false,
+									MinValue:    &minHeatDecay,
+									MaxValue:    MAX_HEAT,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "reset",
+									Description: "Cools the current heat down to zero.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "loot",
+							Description: "Sets the percentage of the vault that is stolen.",
+							Handler:     configLoot,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "percent",
+									Description: "The percentage of the vault that is stolen.",
+									Required:    true,
+									MinValue:    &minLootPercent,
+									MaxValue:    maxLootPercent,
 								},
 							},
 						},
 						{
 							Name:        "sentence",
 							Description: "Sets the base apprehension time when caught.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configSentence,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
@@ -123,7 +226,7 @@ var (
 						{
 							Name:        "wait",
 							Description: "Sets how long players can gather others for a heist.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     configWait,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionInteger,
@@ -138,16 +241,16 @@ var (
 				{
 					Name:        "theme",
 					Description: "Commands that interact with the heist themes.",
-					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
-					Options: []*discordgo.ApplicationCommandOption{
+					Subcommands: []*discord.Command{
 						{
 							Name:        "list",
 							Description: "Gets the list of available heist themes.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Handler:     listThemes,
 						},
 						{
 							Name:        "set",
 							Description: "Sets the current heist theme.",
+							Handler:     setTheme,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
 									Type:        discordgo.ApplicationCommandOptionString,
@@ -156,28 +259,27 @@ var (
 									Required:    true,
 								},
 							},
-							Type: discordgo.ApplicationCommandOptionSubCommand,
 						},
 					},
 				},
 				{
 					Name:        "reset",
 					Description: "Resets a new heist that is hung.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     resetHeist,
 				},
 			},
 		},
-	}
+	)
 
-	memberCommands = []*discordgo.ApplicationCommand{
-		{
+	memberRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "heist",
 			Description: "Heist game commands.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "bail",
 					Description: "Bail a player out of jail.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     bailoutPlayer,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -187,108 +289,47 @@ var (
 						},
 					},
 				},
+				{
+					Name:        "leaderboard",
+					Description: "Shows the heist leaderboard.",
+					Handler:     heistLeaderboard,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "metric",
+							Description: "The statistic used to rank the members.",
+							Required:    true,
+							Choices:     getLeaderboardChoices(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "page",
+							Description: "The page of the leaderboard to show.",
+							Required:    false,
+							MinValue:    &minLeaderboardPage,
+						},
+					},
+				},
 				{
 					Name:        "stats",
 					Description: "Shows a user's stats.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     playerStats,
 				},
 				{
 					Name:        "start",
 					Description: "Plans a new heist.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     planHeist,
 				},
 				{
 					Name:        "targets",
 					Description: "Gets the list of available heist targets.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     listTargets,
 				},
 			},
 		},
-	}
+	)
 )
 
-// config routes the configuration commands to the proper handlers.
-func config(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> heist.config")
-	defer log.Trace("<-- heist.config")
-
-	options := i.ApplicationCommandData().Options[0].Options
-	switch options[0].Name {
-	case "cost":
-		configCost(s, i)
-	case "sentence":
-		configSentence(s, i)
-	case "patrol":
-		configPatrol(s, i)
-	case "bail":
-		configBail(s, i)
-	case "death":
-		configDeath(s, i)
-	case "wait":
-		configWait(s, i)
-	case "info":
-		configInfo(s, i)
-	}
-}
-
-// heistAdmin routes the commands to the subcommand and subcommandgroup handlers
-func heistAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> heist.heistAmin")
-	defer log.Trace("<-- heist.heistAdmin")
-
-	p := discmsg.GetPrinter(language.AmericanEnglish)
-
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "clear":
-		clearMember(s, i)
-	case "config":
-		config(s, i)
-	case "reset":
-		resetHeist(s, i)
-	case "theme":
-		theme(s, i)
-	}
-}
-
-// heist routes the commands to the subcommand and subcommandgroup handlers
-func heist(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> heist")
-	defer log.Trace("<-- heist")
-
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "bail":
-		bailoutPlayer(s, i)
-	case "start":
-		planHeist(s, i)
-	case "stats":
-		playerStats(s, i)
-	case "targets":
-		listTargets(s, i)
-	}
-}
-
-// theme routes the theme commands to the proper handlers.
-func theme(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> heist.theme")
-	defer log.Trace("<-- heist.theme")
-
-	options := i.ApplicationCommandData().Options[0].Options
-	switch options[0].Name {
-	case "list":
-		listThemes(s, i)
-	case "set":
-		setTheme(s, i)
-	}
-}
-
 // planHeist plans a new heist
 func planHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> heist.planHeist")
@@ -298,7 +339,7 @@ func planHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	discmsg.SendResponse(s, i, "Starting a "+theme.Heist+"...")
 
 	// Create a new heist
-	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.DisplayName())
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID)
 	heist, err := NewHeist(i.GuildID, guildMember)
 	if err != nil {
 		log.WithField("error", err).Error("unable to create the heist")
@@ -306,16 +347,24 @@ func planHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 	heist.interaction = i
+	heist.channelID = i.ChannelID
+	heistMsg, err := s.InteractionResponse(i.Interaction)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Warn("unable to get the heist message")
+	} else {
+		heist.messageID = heistMsg.ID
+	}
 
 	// The organizer has to pay a fee to plan the heist.
 	account := bank.GetAccount(i.GuildID, guildMember.MemberID)
 	account.Withdraw(heist.config.HeistCost)
+	heist.saveState()
 
 	heistMessage(s, i, heist, guildMember, "plan")
 
 	waitForHeistToStart(s, i, heist)
 
-	if len(heist.Crew) < 2 {
+	if len(heist.Crew) < heist.config.MinCrew {
 		heistMessage(s, i, heist, guildMember, "cancel")
 		p := discmsg.GetPrinter(language.AmericanEnglish)
 		msg := p.Sprintf("The %s was cancelled due to lack of interest.", heist.theme.Heist)
@@ -324,6 +373,7 @@ func planHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		heistLock.Lock()
 		defer heistLock.Unlock()
 		delete(currentHeists, heist.GuildID)
+		deleteHeistState(heist.GuildID)
 		return
 	}
 
@@ -331,6 +381,8 @@ func planHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	mute := channel.NewChannelMute(s, i)
 	mute.MuteChannel()
 	defer mute.UnmuteChannel()
+	heist.mute = mute
+	heist.setStage(HEIST_STARTED)
 
 	err = heistMessage(s, i, heist, guildMember, "start")
 	if err != nil {
@@ -438,24 +490,33 @@ func sendHeistResults(s *discordgo.Session, i *discordgo.InteractionCreate, res
 		s.ChannelMessageSend(i.ChannelID, "```\n"+tableBuffer.String()+"```")
 	}
 
+	// Once the spoils start being distributed, the crew should no longer be refunded if the bot is restarted.
+	res.heist.setStage(HEIST_COMPLETE)
+
 	// Update the status for each player and then save the information
 	for _, result := range res.AllResults {
+		var loot int
+		if len(res.Escaped) > 0 && result.StolenCredits != 0 {
+			loot = result.StolenCredits + result.BonusCredits
+		}
+
 		result.Player.heist = result.heist
 		switch result.Status {
 		case APPREHENDED:
-			result.Player.Apprehended()
+			result.Player.Apprehended(loot)
 		case DEAD:
 			result.Player.Died()
 		default:
-			result.Player.Escaped()
+			result.Player.Escaped(loot)
 		}
 
-		if len(res.Escaped) > 0 && result.StolenCredits != 0 {
+		if loot != 0 {
 			account := bank.GetAccount(i.GuildID, result.Player.MemberID)
-			account.Deposit(result.StolenCredits + result.BonusCredits)
+			account.Deposit(loot)
 			log.WithFields(log.Fields{"Member": account.MemberID, "Stolen": result.StolenCredits, "Bonus": result.BonusCredits}).Debug("heist Loot")
 		}
 	}
+	writeHeistRecord(newHeistRecord(res))
 
 	heistLock.Lock()
 	h := currentHeists[i.GuildID]
@@ -463,8 +524,9 @@ func sendHeistResults(s *discordgo.Session, i *discordgo.InteractionCreate, res
 	guildMember := h.Organizer.guildMember
 	heistMessage(s, i, h, guildMember, "ended")
 
-	// Update the heist status information
-	h.config.AlertTime = time.Now().Add(h.config.PoliceAlert)
+	// Each heist, and each member who escapes, draws more attention from the police.
+	config := GetConfig(i.GuildID)
+	config.AddHeat(config.HeatPerHeist + config.HeatPerSuccess*len(res.Escaped))
 }
 
 // joinHeist attempts to join a heist that is being planned
@@ -472,7 +534,7 @@ func joinHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> joinHeist")
 	defer log.Trace("<-- joinHeist")
 
-	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.DisplayName())
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID)
 
 	heist := currentHeists[i.GuildID]
 	if heist == nil {
@@ -492,6 +554,7 @@ func joinHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// has the required number of credits as this is verified when adding them to the heist.
 	account := bank.GetAccount(i.GuildID, guildMember.MemberID)
 	account.Withdraw(heist.config.HeistCost)
+	heist.saveState()
 
 	p := discmsg.GetPrinter(language.AmericanEnglish)
 	resp := p.Sprintf("You have joined the %s at a cost of %d credits.", heist.theme.Heist, heist.config.HeistCost)
@@ -570,6 +633,21 @@ func playerStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 					Value:  fmt.Sprintf("%d", account.CurrentBalance),
 					Inline: true,
 				},
+				{
+					Name:   caser.String(theme.Heist) + "s",
+					Value:  fmt.Sprintf("%d (%d successful)", player.TotalHeists, player.Successful),
+					Inline: true,
+				},
+				{
+					Name:   "Longest Spree",
+					Value:  fmt.Sprintf("%d", player.LongestSpree),
+					Inline: true,
+				},
+				{
+					Name:   "Total Stolen",
+					Value:  fmt.Sprintf("%d", player.TotalStolen),
+					Inline: true,
+				},
 			},
 		},
 	}
@@ -713,6 +791,11 @@ func heistMessage(s *discordgo.Session, i *discordgo.InteractionCreate, heist *H
 					Value:  strings.Join(crew, ", "),
 					Inline: true,
 				},
+				{
+					Name:   fmt.Sprintf("%s Heat", caser.String(heist.theme.Police)),
+					Value:  heatLevel(heist.config.GetHeat()),
+					Inline: true,
+				},
 			},
 		},
 	}
@@ -755,6 +838,7 @@ func resetHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	heist := currentHeists[i.GuildID]
 	delete(currentHeists, i.GuildID)
 	heistLock.Unlock()
+	deleteHeistState(i.GuildID)
 	if heist == nil {
 		theme := GetTheme(i.GuildID)
 		msg := fmt.Sprintf("No %s is being planned; the channel was un-muted", theme.Heist)
@@ -896,6 +980,110 @@ func configCost(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	writeConfig(config)
 }
 
+// configBonus sets the bonus added to the success rate based on the size of the crew.
+func configBonus(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> configBonus")
+	defer log.Trace("<-- configBonus")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	rates := options[0].StringValue()
+	bonusRates, err := parseBonusRates(rates)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	config.BonusRates = bonusRates
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Bonus rates set to %s", formatBonusRates(bonusRates)))
+	writeConfig(config)
+}
+
+// configCrew sets the minimum and maximum size of a heist crew.
+func configCrew(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> configCrew")
+	defer log.Trace("<-- configCrew")
+
+	config := GetConfig(i.GuildID)
+	minCrew := config.MinCrew
+	maxCrew := config.MaxCrew
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "min":
+			minCrew = int(option.IntValue())
+		case "max":
+			maxCrew = int(option.IntValue())
+		}
+	}
+	if minCrew < 1 {
+		discmsg.SendEphemeralResponse(s, i, "The minimum crew size must be at least 1")
+		return
+	}
+	if maxCrew != 0 && maxCrew < minCrew {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("The maximum crew size must be 0 or at least the minimum crew size of %d", minCrew))
+		return
+	}
+	config.MinCrew = minCrew
+	config.MaxCrew = maxCrew
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Crew size set to a minimum of %d and a maximum of %s", minCrew, formatMaxCrew(maxCrew)))
+	writeConfig(config)
+}
+
+// configEscaped sets the number of shares of the loot received by those who escape.
+func configEscaped(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> configEscaped")
+	defer log.Trace("<-- configEscaped")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	shares := options[0].IntValue()
+	if shares < int64(minEscapedShare) || shares > int64(maxEscapedShare) {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("Shares must be between %d and %d", int(minEscapedShare), int(maxEscapedShare)))
+		return
+	}
+	config.EscapedShare = int(shares)
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Escaped shares set to %d", shares))
+	writeConfig(config)
+}
+
+// configLoot sets the percentage of the vault that is stolen.
+func configLoot(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> configLoot")
+	defer log.Trace("<-- configLoot")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	percent := options[0].IntValue()
+	if percent < int64(minLootPercent) || percent > int64(maxLootPercent) {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("Loot percent must be between %d and %d", int(minLootPercent), int(maxLootPercent)))
+		return
+	}
+	config.LootPercent = int(percent)
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Loot set to %d%%", percent))
+	writeConfig(config)
+}
+
+// formatBonusRates returns the bonus rates in the same format used to configure them.
+func formatBonusRates(bonusRates []*BonusRate) string {
+	rates := make([]string, 0, len(bonusRates))
+	for _, bonusRate := range bonusRates {
+		rates = append(rates, fmt.Sprintf("%d:%d", bonusRate.CrewPercent, bonusRate.Bonus))
+	}
+	return strings.Join(rates, ",")
+}
+
+// formatMaxCrew returns the maximum crew size, or "unlimited" if there is no limit.
+func formatMaxCrew(maxCrew int) string {
+	if maxCrew == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", maxCrew)
+}
+
 // configSentence sets the base aprehension time when a player is apprehended.
 func configSentence(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> configSentence")
@@ -910,19 +1098,56 @@ func configSentence(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	writeConfig(config)
 }
 
-// configPatrol sets the time authorities will prevent a new heist following one being completed.
-func configPatrol(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> configPatrol")
-	defer log.Trace("<-- configPatrol")
+// configHeat sets how much heat the authorities feel from heists, and how fast it cools off.
+func configHeat(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> configHeat")
+	defer log.Trace("<-- configHeat")
 
 	config := GetConfig(i.GuildID)
+	heatPerHeist := config.HeatPerHeist
+	heatPerSuccess := config.HeatPerSuccess
+	heatDecay := config.HeatDecay
+	reset := false
 	options := i.ApplicationCommandData().Options[0].Options[0].Options
-	patrol := options[0].IntValue()
-	config.PoliceAlert = time.Duration(patrol * int64(time.Second))
+	for _, option := range options {
+		switch option.Name {
+		case "heist":
+			heatPerHeist = int(option.IntValue())
+		case "success":
+			heatPerSuccess = int(option.IntValue())
+		case "decay":
+			heatDecay = int(option.IntValue())
+		case "reset":
+			reset = option.BoolValue()
+		}
+	}
+	if heatPerHeist < 0 || heatPerHeist > MAX_HEAT || heatPerSuccess < 0 || heatPerSuccess > MAX_HEAT {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("Heat must be between 0 and %d", MAX_HEAT))
+		return
+	}
+	if heatDecay < 1 || heatDecay > MAX_HEAT {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("Decay must be between 1 and %d", MAX_HEAT))
+		return
+	}
 
-	discmsg.SendResponse(s, i, fmt.Sprintf("Patrol set to %d", patrol))
+	// Bring the current heat up to date before changing how fast it decays.
+	config.Heat = config.GetHeat()
+	config.HeatUpdated = time.Now()
+	config.HeatPerHeist = heatPerHeist
+	config.HeatPerSuccess = heatPerSuccess
+	config.HeatDecay = heatDecay
+	if reset {
+		config.ResetHeat()
+	} else {
+		writeConfig(config)
+	}
 
-	writeConfig(config)
+	discmsg.SendResponse(s, i, fmt.Sprintf("Heat set to %d per heist and %d per success, cooling off by %d each hour. Current heat is %s",
+		heatPerHeist,
+		heatPerSuccess,
+		heatDecay,
+		heatLevel(config.Heat),
+	))
 }
 
 // configBail sets the base cost of bail.
@@ -948,7 +1173,7 @@ func configDeath(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
 	options := i.ApplicationCommandData().Options[0].Options[0].Options
 	death := options[0].IntValue()
-	config.PoliceAlert = time.Duration(death * int64(time.Second))
+	config.DeathTimer = time.Duration(death * int64(time.Second))
 
 	discmsg.SendResponse(s, i, fmt.Sprintf("Death set to %d", death))
 
@@ -989,14 +1214,34 @@ func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Value:  fmt.Sprintf("%d", config.HeistCost),
 				Inline: true,
 			},
+			{
+				Name:   "crew",
+				Value:  fmt.Sprintf("%d-%s", config.MinCrew, formatMaxCrew(config.MaxCrew)),
+				Inline: true,
+			},
 			{
 				Name:   "death",
 				Value:  fmt.Sprintf("%.f", config.DeathTimer.Seconds()),
 				Inline: true,
 			},
 			{
-				Name:   "patrol",
-				Value:  fmt.Sprintf("%.f", config.PoliceAlert.Seconds()),
+				Name:   "escaped",
+				Value:  fmt.Sprintf("%d", config.EscapedShare),
+				Inline: true,
+			},
+			{
+				Name:   "loot",
+				Value:  fmt.Sprintf("%d%%", config.LootPercent),
+				Inline: true,
+			},
+			{
+				Name:   "heat",
+				Value:  heatLevel(config.GetHeat()),
+				Inline: true,
+			},
+			{
+				Name:   "heat rates",
+				Value:  fmt.Sprintf("+%d/heist, +%d/success, -%d/hour", config.HeatPerHeist, config.HeatPerSuccess, config.HeatDecay),
 				Inline: true,
 			},
 			{
@@ -1009,6 +1254,11 @@ func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Value:  fmt.Sprintf("%.f", config.WaitTime.Seconds()),
 				Inline: true,
 			},
+			{
+				Name:   "bonus",
+				Value:  formatBonusRates(config.BonusRates),
+				Inline: false,
+			},
 		},
 	}
 
@@ -2,6 +2,8 @@ package heist
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,26 +18,56 @@ const (
 	CREW_OUTPUT         = "None"
 	DEATH_TIMER         = time.Duration(45 * time.Second)
 	HEIST_COST          = 1500
-	POLICE_ALERT        = time.Duration(60 * time.Second)
 	SENTENCE_BASE       = time.Duration(45 * time.Second)
 	WAIT_TIME           = time.Duration(60 * time.Second)
 	HEIST_DEFAULT_THEME = "clash"
+	LOOT_PERCENT        = 75
+	ESCAPED_SHARE       = 2
+	MIN_CREW            = 2
+	MAX_CREW            = 0 // No limit on the size of the crew
+)
+
+var (
+	// defaultBonusRates are the bonus amounts added to the success rate, based on how close the crew
+	// is to the crew size for the target.
+	defaultBonusRates = []*BonusRate{
+		{CrewPercent: 20, Bonus: 0},
+		{CrewPercent: 40, Bonus: 1},
+		{CrewPercent: 60, Bonus: 3},
+		{CrewPercent: 80, Bonus: 4},
+		{CrewPercent: 100, Bonus: 5},
+	}
 )
 
 // Configuration data for new heists
 type Config struct {
-	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID      string             `json:"guild_id" bson:"guild_id"`
-	Theme        string             `json:"theme" bson:"theme"`
-	AlertTime    time.Time          `json:"alert_time" bson:"alert_time"`
-	BailBase     int                `json:"bail_base" bson:"bail_base"`
-	CrewOutput   string             `json:"crew_output" bson:"crew_output"`
-	DeathTimer   time.Duration      `json:"death_timer" bson:"death_timer"`
-	HeistCost    int                `json:"heist_cost" bson:"heist_cost"`
-	PoliceAlert  time.Duration      `json:"police_alert" bson:"police_alert"`
-	SentenceBase time.Duration      `json:"sentence_base" bson:"sentence_base"`
-	Targets      string             `json:"targets" bson:"targets"`
-	WaitTime     time.Duration      `json:"wait_time" bson:"wait_time"`
+	ID             primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID        string             `json:"guild_id" bson:"guild_id"`
+	Theme          string             `json:"theme" bson:"theme"`
+	BailBase       int                `json:"bail_base" bson:"bail_base"`
+	CrewOutput     string             `json:"crew_output" bson:"crew_output"`
+	DeathTimer     time.Duration      `json:"death_timer" bson:"death_timer"`
+	HeistCost      int                `json:"heist_cost" bson:"heist_cost"`
+	SentenceBase   time.Duration      `json:"sentence_base" bson:"sentence_base"`
+	Targets        string             `json:"targets" bson:"targets"`
+	WaitTime       time.Duration      `json:"wait_time" bson:"wait_time"`
+	LootPercent    int                `json:"loot_percent" bson:"loot_percent"`
+	EscapedShare   int                `json:"escaped_share" bson:"escaped_share"`
+	MinCrew        int                `json:"min_crew" bson:"min_crew"`
+	MaxCrew        int                `json:"max_crew" bson:"max_crew"`
+	BonusRates     []*BonusRate       `json:"bonus_rates" bson:"bonus_rates"`
+	Heat           float64            `json:"heat" bson:"heat"`
+	HeatUpdated    time.Time          `json:"heat_updated" bson:"heat_updated"`
+	HeatPerHeist   int                `json:"heat_per_heist" bson:"heat_per_heist"`
+	HeatPerSuccess int                `json:"heat_per_success" bson:"heat_per_success"`
+	HeatDecay      int                `json:"heat_decay" bson:"heat_decay"`
+}
+
+// BonusRate is the bonus added to the success rate of a heist when the size of the crew is at
+// or below the given percentage of the crew size for the target.
+type BonusRate struct {
+	CrewPercent int `json:"crew_percent" bson:"crew_percent"`
+	Bonus       int `json:"bonus" bson:"bonus"`
 }
 
 // GetConfig retrieves the heist configuration for the specified guild. If
@@ -45,33 +77,95 @@ func GetConfig(guildID string) *Config {
 	if config == nil {
 		config = NewConfig(guildID)
 	}
+	config.setDefaults()
 	return config
 }
 
 // NewConfig creates a new default configuration for the specified guild.
 func NewConfig(guildID string) *Config {
 	config := &Config{
-		GuildID:      guildID,
-		AlertTime:    time.Time{},
-		BailBase:     BAIL_BASE,
-		CrewOutput:   CREW_OUTPUT,
-		DeathTimer:   DEATH_TIMER,
-		HeistCost:    HEIST_COST,
-		PoliceAlert:  POLICE_ALERT,
-		SentenceBase: SENTENCE_BASE,
-		Targets:      HEIST_DEFAULT_THEME,
-		Theme:        HEIST_DEFAULT_THEME,
-		WaitTime:     WAIT_TIME,
+		GuildID:        guildID,
+		BailBase:       BAIL_BASE,
+		CrewOutput:     CREW_OUTPUT,
+		DeathTimer:     DEATH_TIMER,
+		HeistCost:      HEIST_COST,
+		SentenceBase:   SENTENCE_BASE,
+		Targets:        HEIST_DEFAULT_THEME,
+		Theme:          HEIST_DEFAULT_THEME,
+		WaitTime:       WAIT_TIME,
+		LootPercent:    LOOT_PERCENT,
+		EscapedShare:   ESCAPED_SHARE,
+		MinCrew:        MIN_CREW,
+		MaxCrew:        MAX_CREW,
+		BonusRates:     getDefaultBonusRates(),
+		HeatPerHeist:   HEAT_PER_HEIST,
+		HeatPerSuccess: HEAT_PER_SUCCESS,
+		HeatDecay:      HEAT_DECAY,
 	}
 	writeConfig(config)
 
 	return config
 }
 
-// SetAlertTime sets the alert time to the current time plus the police alert
-func (c *Config) SetAlertTime() {
-	c.AlertTime = time.Now().Add(c.PoliceAlert)
-	writeConfig(c)
+// setDefaults sets the default values for any configuration fields that were added after the
+// configuration was saved to the database.
+func (c *Config) setDefaults() {
+	if c.LootPercent == 0 {
+		c.LootPercent = LOOT_PERCENT
+	}
+	if c.EscapedShare == 0 {
+		c.EscapedShare = ESCAPED_SHARE
+	}
+	if c.MinCrew == 0 {
+		c.MinCrew = MIN_CREW
+	}
+	if len(c.BonusRates) == 0 {
+		c.BonusRates = getDefaultBonusRates()
+	}
+	if c.HeatDecay == 0 {
+		c.HeatPerHeist = HEAT_PER_HEIST
+		c.HeatPerSuccess = HEAT_PER_SUCCESS
+		c.HeatDecay = HEAT_DECAY
+	}
+}
+
+// getDefaultBonusRates returns a copy of the default bonus rates.
+func getDefaultBonusRates() []*BonusRate {
+	bonusRates := make([]*BonusRate, 0, len(defaultBonusRates))
+	for _, bonusRate := range defaultBonusRates {
+		bonusRates = append(bonusRates, &BonusRate{CrewPercent: bonusRate.CrewPercent, Bonus: bonusRate.Bonus})
+	}
+	return bonusRates
+}
+
+// parseBonusRates parses a list of bonus rates in the form `percent:bonus,percent:bonus,...`. The
+// crew percentages must be between 1 and 100 and be in increasing order, and the last one must be 100.
+func parseBonusRates(s string) ([]*BonusRate, error) {
+	entries := strings.Split(s, ",")
+	bonusRates := make([]*BonusRate, 0, len(entries))
+	for _, entry := range entries {
+		percentStr, bonusStr, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			return nil, ErrInvalidBonusRates
+		}
+		percent, err := strconv.Atoi(strings.TrimSpace(percentStr))
+		if err != nil || percent < 1 || percent > 100 {
+			return nil, ErrInvalidBonusRates
+		}
+		bonus, err := strconv.Atoi(strings.TrimSpace(bonusStr))
+		if err != nil || bonus < 0 || bonus > 100 {
+			return nil, ErrInvalidBonusRates
+		}
+		if len(bonusRates) > 0 && percent <= bonusRates[len(bonusRates)-1].CrewPercent {
+			return nil, ErrInvalidBonusRates
+		}
+		bonusRates = append(bonusRates, &BonusRate{CrewPercent: percent, Bonus: bonus})
+	}
+	if bonusRates[len(bonusRates)-1].CrewPercent != 100 {
+		return nil, ErrInvalidBonusRates
+	}
+
+	return bonusRates, nil
 }
 
 // String returns a string representation of the heist configuration
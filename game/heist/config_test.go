@@ -49,8 +49,14 @@ func TestGetConfig(t *testing.T) {
 	if config.HeistCost != HEIST_COST {
 		t.Errorf("Expected %d, got %d", HEIST_COST, config.HeistCost)
 	}
-	if config.PoliceAlert != POLICE_ALERT {
-		t.Errorf("Expected %d, got %d", POLICE_ALERT, config.PoliceAlert)
+	if config.HeatPerHeist != HEAT_PER_HEIST {
+		t.Errorf("Expected %d, got %d", HEAT_PER_HEIST, config.HeatPerHeist)
+	}
+	if config.HeatPerSuccess != HEAT_PER_SUCCESS {
+		t.Errorf("Expected %d, got %d", HEAT_PER_SUCCESS, config.HeatPerSuccess)
+	}
+	if config.HeatDecay != HEAT_DECAY {
+		t.Errorf("Expected %d, got %d", HEAT_DECAY, config.HeatDecay)
 	}
 	if config.SentenceBase != SENTENCE_BASE {
 		t.Errorf("Expected %d, got %d", SENTENCE_BASE, config.SentenceBase)
@@ -61,4 +67,42 @@ func TestGetConfig(t *testing.T) {
 	if config.Targets != HEIST_DEFAULT_THEME {
 		t.Errorf("Expected empty string, got %s", config.Targets)
 	}
+	if config.LootPercent != LOOT_PERCENT {
+		t.Errorf("Expected %d, got %d", LOOT_PERCENT, config.LootPercent)
+	}
+	if config.EscapedShare != ESCAPED_SHARE {
+		t.Errorf("Expected %d, got %d", ESCAPED_SHARE, config.EscapedShare)
+	}
+	if config.MinCrew != MIN_CREW {
+		t.Errorf("Expected %d, got %d", MIN_CREW, config.MinCrew)
+	}
+	if config.MaxCrew != MAX_CREW {
+		t.Errorf("Expected %d, got %d", MAX_CREW, config.MaxCrew)
+	}
+	if len(config.BonusRates) != len(defaultBonusRates) {
+		t.Errorf("Expected %d, got %d", len(defaultBonusRates), len(config.BonusRates))
+	}
+}
+
+func TestParseBonusRates(t *testing.T) {
+	bonusRates, err := parseBonusRates("25:1, 50:2,100:4")
+	if err != nil {
+		t.Errorf("Expected no error, got %s", err)
+		return
+	}
+	if len(bonusRates) != 3 {
+		t.Errorf("Expected 3, got %d", len(bonusRates))
+		return
+	}
+	if bonusRates[1].CrewPercent != 50 || bonusRates[1].Bonus != 2 {
+		t.Errorf("Expected 50:2, got %d:%d", bonusRates[1].CrewPercent, bonusRates[1].Bonus)
+	}
+
+	invalid := []string{"", "50", "50:1", "50:1,40:2,100:3", "0:1,100:2", "50:-1,100:2", "a:1,100:2"}
+	for _, rates := range invalid {
+		_, err := parseBonusRates(rates)
+		if err == nil {
+			t.Errorf("Expected an error for %q", rates)
+		}
+	}
 }
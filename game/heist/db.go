@@ -10,6 +10,8 @@ import (
 const (
 	CONFIG_COLLECTION       = "heist_configs"
 	HEIST_MEMBER_COLLECTION = "heist_members"
+	HEIST_RECORD_COLLECTION = "heist_records"
+	HEIST_STATE_COLLECTION  = "heist_states"
 	TARGET_COLLECTION       = "heist_targets"
 	THEME_COLLECTION        = "heist_themes"
 )
@@ -182,3 +184,100 @@ func writeTheme(theme *Theme) {
 	db.UpdateOrInsert(THEME_COLLECTION, filter, theme)
 	log.WithFields(log.Fields{"guild": theme.GuildID, "theme": theme.Name}).Debug("write theme to the database")
 }
+
+// readHeistStates loads the saved state for all heists that had not ended when the bot was stopped.
+func readHeistStates() ([]*HeistState, error) {
+	log.Trace("--> heist.readHeistStates")
+	defer log.Trace("<-- heist.readHeistStates")
+
+	var states []*HeistState
+	err := db.FindMany(HEIST_STATE_COLLECTION, bson.D{}, &states, bson.D{}, 0)
+	if err != nil {
+		log.WithField("error", err).Error("unable to read heist states")
+		return nil, err
+	}
+
+	log.WithField("states", len(states)).Debug("read heist states")
+
+	return states, nil
+}
+
+// writeHeistState creates or updates the saved state of the heist for a guild in the database.
+func writeHeistState(state *HeistState) {
+	log.Trace("--> heist.writeHeistState")
+	defer log.Trace("<-- heist.writeHeistState")
+
+	filter := bson.M{"guild_id": state.GuildID}
+	err := db.UpdateOrInsert(HEIST_STATE_COLLECTION, filter, state)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": state.GuildID, "error": err}).Error("unable to save the heist state to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": state.GuildID, "stage": state.Stage}).Debug("write heist state to the database")
+}
+
+// deleteHeistState removes the saved state of the heist for a guild from the database.
+func deleteHeistState(guildID string) {
+	log.Trace("--> heist.deleteHeistState")
+	defer log.Trace("<-- heist.deleteHeistState")
+
+	filter := bson.M{"guild_id": guildID}
+	err := db.Delete(HEIST_STATE_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to delete the heist state from the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": guildID}).Debug("delete heist state from the database")
+}
+
+// readHeistMemberPage loads a page of heist members for a guild, sorted in descending order by
+// the given field. Members whose value for the field is zero are not included.
+func readHeistMemberPage(guildID string, field string, skip int, limit int) ([]*HeistMember, error) {
+	log.Trace("--> heist.readHeistMemberPage")
+	defer log.Trace("<-- heist.readHeistMemberPage")
+
+	var members []*HeistMember
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: field, Value: bson.M{"$gt": 0}}}
+	sort := bson.D{{Key: field, Value: -1}, {Key: "member_id", Value: 1}}
+	err := db.FindPage(HEIST_MEMBER_COLLECTION, filter, &members, sort, int64(skip), int64(limit))
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "field": field, "error": err}).Error("unable to read heist members")
+		return nil, err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "field": field, "skip": skip, "members": len(members)}).Debug("read heist members")
+
+	return members, nil
+}
+
+// countHeistMembers returns the number of heist members for a guild whose value for the given
+// field is greater than zero.
+func countHeistMembers(guildID string, field string) int {
+	log.Trace("--> heist.countHeistMembers")
+	defer log.Trace("<-- heist.countHeistMembers")
+
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: field, Value: bson.M{"$gt": 0}}}
+	count, err := db.Count(HEIST_MEMBER_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "field": field, "error": err}).Error("unable to count heist members")
+		return 0
+	}
+
+	return count
+}
+
+// writeHeistRecord saves the outcome of a heist to the database.
+func writeHeistRecord(record *HeistRecord) {
+	log.Trace("--> heist.writeHeistRecord")
+	defer log.Trace("<-- heist.writeHeistRecord")
+
+	if record.ID == primitive.NilObjectID {
+		record.ID = primitive.NewObjectID()
+	}
+	filter := bson.M{"_id": record.ID}
+	err := db.UpdateOrInsert(HEIST_RECORD_COLLECTION, filter, record)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": record.GuildID, "error": err}).Error("unable to save the heist record to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": record.GuildID, "target": record.Target}).Debug("write heist record to the database")
+}
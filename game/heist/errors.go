@@ -14,6 +14,8 @@ var (
 	ErrConfigNotFound      = errors.New("configuration file not found")
 	ErrHeistInProgress     = errors.New("heist already in progress")
 	ErrAlreadyJoinedHieist = errors.New("you have already joined the heist")
+	ErrCrewFull            = errors.New("the crew is already full")
+	ErrInvalidBonusRates   = errors.New("bonus rates must be a list of `percent:bonus` pairs, such as `20:0,40:1,60:3,80:4,100:5`, with increasing percentages ending at 100")
 	ErrNoHeist             = errors.New("heist not found")
 	ErrNotAllowed          = errors.New("user is not allowed to perform command")
 	ErrThemeNotFound       = errors.New("theme not found")
@@ -0,0 +1,101 @@
+package heist
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	MAX_HEAT             = 100
+	HEAT_PER_HEIST       = 20
+	HEAT_PER_SUCCESS     = 5
+	HEAT_DECAY           = 20 // Heat lost per hour
+	HEAT_SUCCESS_PENALTY = 15 // Maximum reduction in the success rate when the heat is at its maximum
+	HEAT_SENTENCE_FACTOR = 1  // Maximum increase in the length of a sentence when the heat is at its maximum
+)
+
+// GetHeat returns the current heat for the guild, after accounting for the heat that has
+// decayed since it was last updated.
+func (c *Config) GetHeat() float64 {
+	return c.heatAt(time.Now())
+}
+
+// heatAt returns the heat at the given time, after accounting for decay since the heat was
+// last updated.
+func (c *Config) heatAt(now time.Time) float64 {
+	if c.Heat <= 0 {
+		return 0
+	}
+	hours := now.Sub(c.HeatUpdated).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+	heat := c.Heat - hours*float64(c.HeatDecay)
+	return math.Max(heat, 0)
+}
+
+// AddHeat increases the heat for the guild by the given amount, capped at the maximum heat, and
+// saves the configuration.
+func (c *Config) AddHeat(amount int) {
+	log.Trace("--> heist.Config.AddHeat")
+	defer log.Trace("<-- heist.Config.AddHeat")
+
+	now := time.Now()
+	c.Heat = math.Min(c.heatAt(now)+float64(amount), MAX_HEAT)
+	c.HeatUpdated = now
+	writeConfig(c)
+
+	log.WithFields(log.Fields{"guild": c.GuildID, "amount": amount, "heat": c.Heat}).Debug("add heat")
+}
+
+// ResetHeat cools the heat for the guild down to zero and saves the configuration.
+func (c *Config) ResetHeat() {
+	log.Trace("--> heist.Config.ResetHeat")
+	defer log.Trace("<-- heist.Config.ResetHeat")
+
+	c.Heat = 0
+	c.HeatUpdated = time.Now()
+	writeConfig(c)
+
+	log.WithFields(log.Fields{"guild": c.GuildID}).Debug("reset heat")
+}
+
+// successPenalty returns the amount by which the success rate of a heist is reduced due
+// to the current heat.
+func (c *Config) successPenalty() int {
+	return int(math.Round(c.GetHeat() * HEAT_SUCCESS_PENALTY / MAX_HEAT))
+}
+
+// sentenceMultiplier returns the multiplier applied to sentences due to the current heat.
+func (c *Config) sentenceMultiplier() float64 {
+	return 1 + c.GetHeat()*HEAT_SENTENCE_FACTOR/MAX_HEAT
+}
+
+// lockdownRemaining returns how long until the heat has cooled off enough for another heist
+// to be planned. If a heist may be planned now, then zero is returned.
+func (c *Config) lockdownRemaining() time.Duration {
+	excess := c.GetHeat() + float64(c.HeatPerHeist) - MAX_HEAT
+	if excess <= 0 || c.HeatDecay <= 0 {
+		return 0
+	}
+	return time.Duration(excess / float64(c.HeatDecay) * float64(time.Hour)).Round(time.Second)
+}
+
+// heatLevel returns a description of the given heat.
+func heatLevel(heat float64) string {
+	var level string
+	switch {
+	case heat < 25:
+		level = "Cold"
+	case heat < 50:
+		level = "Warm"
+	case heat < 75:
+		level = "Hot"
+	default:
+		level = "Blazing"
+	}
+	return fmt.Sprintf("%s (%.0f/%d)", level, heat, MAX_HEAT)
+}
@@ -0,0 +1,55 @@
+package heist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeatDecay(t *testing.T) {
+	now := time.Now()
+	config := &Config{
+		Heat:        50,
+		HeatUpdated: now.Add(-2 * time.Hour),
+		HeatDecay:   10,
+	}
+
+	heat := config.heatAt(now)
+	if heat != 30 {
+		t.Errorf("Expected 30, got %.2f", heat)
+	}
+
+	heat = config.heatAt(now.Add(10 * time.Hour))
+	if heat != 0 {
+		t.Errorf("Expected 0, got %.2f", heat)
+	}
+}
+
+func TestHeatPenalties(t *testing.T) {
+	config := &Config{
+		Heat:         MAX_HEAT,
+		HeatUpdated:  time.Now(),
+		HeatPerHeist: HEAT_PER_HEIST,
+		HeatDecay:    HEAT_DECAY,
+	}
+
+	if config.successPenalty() != HEAT_SUCCESS_PENALTY {
+		t.Errorf("Expected %d, got %d", HEAT_SUCCESS_PENALTY, config.successPenalty())
+	}
+	if config.sentenceMultiplier() < 1+HEAT_SENTENCE_FACTOR-0.01 {
+		t.Errorf("Expected %d, got %.2f", 1+HEAT_SENTENCE_FACTOR, config.sentenceMultiplier())
+	}
+	if config.lockdownRemaining() <= 0 {
+		t.Errorf("Expected a lockdown, got none")
+	}
+
+	config.Heat = 0
+	if config.successPenalty() != 0 {
+		t.Errorf("Expected 0, got %d", config.successPenalty())
+	}
+	if config.sentenceMultiplier() != 1 {
+		t.Errorf("Expected 1, got %.2f", config.sentenceMultiplier())
+	}
+	if config.lockdownRemaining() != 0 {
+		t.Errorf("Expected no lockdown, got %s", config.lockdownRemaining())
+	}
+}
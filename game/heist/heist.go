@@ -11,6 +11,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/channel"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,6 +30,10 @@ type Heist struct {
 	theme       *Theme
 	interaction *discordgo.InteractionCreate
 	config      *Config
+	channelID   string
+	messageID   string
+	mute        *channel.Mute
+	stage       string
 	mutex       sync.Mutex
 }
 
@@ -75,6 +80,7 @@ func NewHeist(guildID string, member *guild.Member) (*Heist, error) {
 		config:    GetConfig(guildID),
 		targets:   GetTargets(guildID, theme.Name),
 		theme:     theme,
+		stage:     HEIST_PLANNING,
 		mutex:     sync.Mutex{},
 	}
 
@@ -117,7 +123,7 @@ func (h *Heist) Start() (*HeistResult, error) {
 	log.Trace("--> heist.Heist.Start")
 	defer log.Trace("<-- heist.Heist.Start")
 
-	if len(h.Crew) < 2 {
+	if len(h.Crew) < h.config.MinCrew {
 		log.WithFields(log.Fields{"guild": h.GuildID}).Error("not enough members to start heist")
 		return nil, ErrNotEnoughMembers{*h.theme}
 	}
@@ -210,11 +216,10 @@ func (h *Heist) End() {
 	log.Trace("--> heist.Heist.End")
 	defer log.Trace("<-- heist.Heist.End")
 
-	h.config.SetAlertTime()
-
 	heistLock.Lock()
 	defer heistLock.Unlock()
 	delete(currentHeists, h.GuildID)
+	deleteHeistState(h.GuildID)
 
 	log.WithFields(log.Fields{"guild": h.GuildID}).Debug("heist ended")
 }
@@ -233,14 +238,19 @@ func heistChecks(h *Heist, member *HeistMember) error {
 		return ErrAlreadyJoinedHieist
 	}
 
+	if h.config.MaxCrew > 0 && len(h.Crew) >= h.config.MaxCrew {
+		log.WithFields(log.Fields{"guild": h.GuildID, "member": member.MemberID, "maxCrew": h.config.MaxCrew}).Debug("heist crew is full")
+		return ErrCrewFull
+	}
+
 	account := bank.GetAccount(h.GuildID, member.MemberID)
 
 	if account.CurrentBalance < h.config.HeistCost {
 		return &ErrNotEnoughCredits{h.config.HeistCost}
 	}
 
-	if h.config.AlertTime.After(time.Now()) {
-		remainingTime := time.Until(h.config.AlertTime)
+	remainingTime := h.config.lockdownRemaining()
+	if remainingTime > 0 {
 		return &ErrPoliceOnAlert{h.theme.Police, remainingTime}
 	}
 
@@ -267,8 +277,9 @@ func calculateSuccessRate(heist *Heist, target *Target) int {
 	defer log.Trace("<-- heist.calculateSuccessRate")
 
 	bonus := calculateBonusRate(heist, target)
-	successChance := int(math.Round(target.Success)) + bonus
-	log.WithFields(log.Fields{"BonusRate": bonus, "TargetSuccess": math.Round(target.Success), "SuccessChance": successChance}).Debug("Success Rate")
+	penalty := heist.config.successPenalty()
+	successChance := max(int(math.Round(target.Success))+bonus-penalty, 0)
+	log.WithFields(log.Fields{"BonusRate": bonus, "HeatPenalty": penalty, "TargetSuccess": math.Round(target.Success), "SuccessChance": successChance}).Debug("Success Rate")
 	return successChance
 }
 
@@ -281,19 +292,16 @@ func calculateBonusRate(heist *Heist, target *Target) int {
 
 	percent := 100 * len(heist.Crew) / target.CrewSize
 	log.WithField("percent", percent).Debug("percentage for calculating success bonus")
-	if percent <= 20 {
-		return 0
-	}
-	if percent <= 40 {
-		return 1
-	}
-	if percent <= 60 {
-		return 3
+	bonusRates := heist.config.BonusRates
+	for _, bonusRate := range bonusRates {
+		if percent <= bonusRate.CrewPercent {
+			return bonusRate.Bonus
+		}
 	}
-	if percent <= 80 {
-		return 4
+	if len(bonusRates) == 0 {
+		return 0
 	}
-	return 5
+	return bonusRates[len(bonusRates)-1].Bonus
 }
 
 // calculateCredits determines the number of credits stolen by each surviving crew member.
@@ -301,21 +309,23 @@ func calculateCredits(results *HeistResult) {
 	log.Trace("--> heist.calculateCredits")
 	defer log.Trace("<-- heist.calculateCredits")
 
-	// Take 3/4 of the amount of the vault, and distribute it among those who survived.
+	config := results.heist.config
+
+	// Take the configured percentage of the vault, and distribute it among those who survived.
 	numEscaped := len(results.Escaped)
 	numApprehended := len(results.Apprehended)
 	numSurvived := numEscaped + numApprehended
-	stolenPerSurivor := int(math.Round(float64(results.Target.Vault) * 0.75 / float64(numSurvived)))
+	stolenPerSurivor := int(math.Round(float64(results.Target.Vault) * float64(config.LootPercent) / 100 / float64(numSurvived)))
 	totalStolen := numSurvived * stolenPerSurivor
 
-	// Get a "base amount" of loot stolen. If you are apprehended, this is what you get. If you escaped you get 2x as much.
-	baseStolen := totalStolen / (2*numEscaped + numApprehended)
+	// Get a "base amount" of loot stolen. If you are apprehended, this is what you get. If you escaped you get
+	// the configured multiple of the base amount.
+	baseStolen := totalStolen / (config.EscapedShare*numEscaped + numApprehended)
 
 	results.TotalStolen = 0
-	// Caculate a "base amount". Those who escape get 2x those who don't. So Divide the
 	log.WithFields(log.Fields{"Target": results.Target.Name, "Vault": results.Target.Vault, "Survivors": numSurvived, "Base Credits": baseStolen}).Debug("Looted")
 	for _, heistMemberResult := range results.Escaped {
-		heistMemberResult.StolenCredits = 2 * baseStolen
+		heistMemberResult.StolenCredits = config.EscapedShare * baseStolen
 		results.TotalStolen += heistMemberResult.StolenCredits
 	}
 	for _, heistMemberResult := range results.Apprehended {
@@ -0,0 +1,248 @@
+package heist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/olekukonko/tablewriter"
+	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+)
+
+const (
+	LEADERBOARD_PAGE_SIZE = 10
+)
+
+// leaderboardMetric is a statistic for heist members that may be ranked on a leaderboard.
+type leaderboardMetric struct {
+	Name   string                 // Name of the metric used by the `/heist leaderboard` command
+	Title  string                 // Title shown for the leaderboard
+	Header string                 // Column header for the value of the metric
+	Field  string                 // Database field for the metric
+	Value  func(*HeistMember) int // Returns the value of the metric for a member
+}
+
+var (
+	leaderboardMetrics = []*leaderboardMetric{
+		{
+			Name:   "stolen",
+			Title:  "Total Stolen",
+			Header: "Stolen",
+			Field:  "total_stolen",
+			Value:  func(m *HeistMember) int { return m.TotalStolen },
+		},
+		{
+			Name:   "successful",
+			Title:  "Successful Heists",
+			Header: "Heists",
+			Field:  "successful_heists",
+			Value:  func(m *HeistMember) int { return m.Successful },
+		},
+		{
+			Name:   "spree",
+			Title:  "Longest Spree",
+			Header: "Spree",
+			Field:  "longest_spree",
+			Value:  func(m *HeistMember) int { return m.LongestSpree },
+		},
+		{
+			Name:   "deaths",
+			Title:  "Most Deaths",
+			Header: "Deaths",
+			Field:  "deaths",
+			Value:  func(m *HeistMember) int { return m.Deaths },
+		},
+	}
+)
+
+// getLeaderboardMetric returns the leaderboard metric with the given name, or `nil` if one
+// doesn't exist.
+func getLeaderboardMetric(name string) *leaderboardMetric {
+	for _, metric := range leaderboardMetrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	return nil
+}
+
+// leaderboardTitle returns the title of the leaderboard for the metric.
+func leaderboardTitle(metric *leaderboardMetric) string {
+	return "Heist Leaderboard: " + metric.Title
+}
+
+// getLeaderboardChoices returns the choices for the metric used in the `/heist leaderboard` command.
+func getLeaderboardChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(leaderboardMetrics))
+	for _, metric := range leaderboardMetrics {
+		choice := &discordgo.ApplicationCommandOptionChoice{
+			Name:  metric.Title,
+			Value: metric.Name,
+		}
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+// heistLeaderboard sends the heist leaderboard for the selected metric.
+func heistLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> heist.heistLeaderboard")
+	defer log.Trace("<-- heist.heistLeaderboard")
+
+	metric := leaderboardMetrics[0]
+	page := 1
+	options := i.ApplicationCommandData().Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "metric":
+			metric = getLeaderboardMetric(option.StringValue())
+		case "page":
+			page = int(option.IntValue())
+		}
+	}
+	if metric == nil {
+		discmsg.SendEphemeralResponse(s, i, "Unknown leaderboard")
+		return
+	}
+
+	embeds, components := getLeaderboardPage(i.GuildID, metric, page)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: components,
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the heist leaderboard")
+	}
+}
+
+// heistLeaderboardPrevious shows the previous page of the heist leaderboard.
+func heistLeaderboardPrevious(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> heist.heistLeaderboardPrevious")
+	defer log.Trace("<-- heist.heistLeaderboardPrevious")
+
+	changeLeaderboardPage(s, i, -1)
+}
+
+// heistLeaderboardNext shows the next page of the heist leaderboard.
+func heistLeaderboardNext(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> heist.heistLeaderboardNext")
+	defer log.Trace("<-- heist.heistLeaderboardNext")
+
+	changeLeaderboardPage(s, i, 1)
+}
+
+// changeLeaderboardPage updates the leaderboard message to show a different page. The metric and
+// the current page are encoded in the custom ID of the button that was pressed.
+func changeLeaderboardPage(s *discordgo.Session, i *discordgo.InteractionCreate, offset int) {
+	log.Trace("--> heist.changeLeaderboardPage")
+	defer log.Trace("<-- heist.changeLeaderboardPage")
+
+	args := discord.ComponentArgs(i)
+	if len(args) != 2 {
+		discmsg.SendEphemeralResponse(s, i, "Unable to find the leaderboard")
+		return
+	}
+	metric := getLeaderboardMetric(args[0])
+	page, err := strconv.Atoi(args[1])
+	if metric == nil || err != nil {
+		discmsg.SendEphemeralResponse(s, i, "Unable to find the leaderboard")
+		return
+	}
+
+	embeds, components := getLeaderboardPage(i.GuildID, metric, page+offset)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: components,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to update the heist leaderboard")
+	}
+}
+
+// getLeaderboardPage returns the embeds and buttons used to show a page of the heist leaderboard.
+// If the page is out of range, the nearest valid page is returned.
+func getLeaderboardPage(guildID string, metric *leaderboardMetric, page int) ([]*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	log.Trace("--> heist.getLeaderboardPage")
+	defer log.Trace("<-- heist.getLeaderboardPage")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	count := countHeistMembers(guildID, metric.Field)
+	pages := max((count+LEADERBOARD_PAGE_SIZE-1)/LEADERBOARD_PAGE_SIZE, 1)
+	page = min(max(page, 1), pages)
+
+	skip := (page - 1) * LEADERBOARD_PAGE_SIZE
+	members, _ := readHeistMemberPage(guildID, metric.Field, skip, LEADERBOARD_PAGE_SIZE)
+
+	var value string
+	if len(members) == 0 {
+		value = "No one has made the leaderboard yet."
+	} else {
+		var tableBuffer strings.Builder
+		table := tablewriter.NewWriter(&tableBuffer)
+		table.SetAutoWrapText(false)
+		table.SetAutoFormatHeaders(true)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetTablePadding("\t")
+		table.SetNoWhiteSpace(true)
+		table.SetHeader([]string{"#", "Name", metric.Header})
+		for idx, member := range members {
+			guildMember := guild.GetMember(guildID, member.MemberID)
+			data := []string{strconv.Itoa(skip + idx + 1), guildMember.Name, p.Sprintf("%d", metric.Value(member))}
+			table.Append(data)
+		}
+		table.Render()
+		value = p.Sprintf("```\n%s```\n", tableBuffer.String())
+	}
+
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Type:  discordgo.EmbedTypeRich,
+			Title: leaderboardTitle(metric),
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Value: value,
+				},
+			},
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Page %d of %d", page, pages),
+			},
+		},
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Previous",
+				Style:    discordgo.SecondaryButton,
+				Disabled: page <= 1,
+				CustomID: discord.CustomID("heist_lb_previous", metric.Name, strconv.Itoa(page)),
+			},
+			discordgo.Button{
+				Label:    "Next",
+				Style:    discordgo.SecondaryButton,
+				Disabled: page >= pages,
+				CustomID: discord.CustomID("heist_lb_next", metric.Name, strconv.Itoa(page)),
+			},
+		}},
+	}
+
+	return embeds, components
+}
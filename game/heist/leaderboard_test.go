@@ -0,0 +1,16 @@
+package heist
+
+import (
+	"testing"
+)
+
+func TestGetLeaderboardMetric(t *testing.T) {
+	for _, metric := range leaderboardMetrics {
+		if getLeaderboardMetric(metric.Name) != metric {
+			t.Errorf("Expected metric %s to be found by name", metric.Name)
+		}
+	}
+	if getLeaderboardMetric("unknown") != nil {
+		t.Errorf("Expected nil for an unknown metric")
+	}
+}
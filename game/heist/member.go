@@ -47,6 +47,10 @@ type HeistMember struct {
 	Spree         int                `json:"spree" bson:"spree"`
 	Status        MemberStatus       `json:"status" bson:"status"`
 	TotalJail     int                `json:"total_jail" bson:"total_jail"`
+	TotalHeists   int                `json:"total_heists" bson:"total_heists"`
+	Successful    int                `json:"successful_heists" bson:"successful_heists"`
+	TotalStolen   int                `json:"total_stolen" bson:"total_stolen"`
+	LongestSpree  int                `json:"longest_spree" bson:"longest_spree"`
 	heist         *Heist             `json:"-" bson:"-"`
 	guildMember   *guild.Member      `json:"-" bson:"-"`
 }
@@ -83,8 +87,9 @@ func newHeistMember(m *guild.Member) *HeistMember {
 	return member
 }
 
-// Apprehended updates the member when they are caught during a heist.
-func (member *HeistMember) Apprehended() {
+// Apprehended updates the member when they are caught during a heist. The loot is the amount
+// of credits the member received from the heist.
+func (member *HeistMember) Apprehended(loot int) {
 	log.Trace("--> heist.Member.Apprehended")
 	log.Trace("<-- heist.Member.Apprehended")
 
@@ -92,7 +97,9 @@ func (member *HeistMember) Apprehended() {
 	if member.Status == OOB {
 		bailCost *= 3
 	}
-	member.Sentence = time.Duration(int64(member.heist.config.SentenceBase) * int64(member.JailCounter+1))
+	// Sentences are longer when the police are feeling the heat from recent heists.
+	sentence := float64(member.heist.config.SentenceBase) * float64(member.JailCounter+1) * member.heist.config.sentenceMultiplier()
+	member.Sentence = time.Duration(sentence).Round(time.Second)
 	member.JailTimer = time.Now().Add(member.Sentence)
 	member.Status = APPREHENDED
 	member.JailCounter++
@@ -100,6 +107,8 @@ func (member *HeistMember) Apprehended() {
 	member.Spree = 0
 	member.CriminalLevel++
 	member.BailCost = bailCost
+	member.TotalHeists++
+	member.TotalStolen += loot
 
 	writeMember(member)
 	log.WithFields(log.Fields{
@@ -132,6 +141,7 @@ func (member *HeistMember) Died() {
 	member.Sentence = 0
 	member.Spree = 0
 	member.Status = DEAD
+	member.TotalHeists++
 
 	writeMember(member)
 
@@ -151,12 +161,17 @@ func (member *HeistMember) Died() {
 	}).Debug("heist member died")
 }
 
-// Escaped updates the member when they successfully escape during a heist.
-func (member *HeistMember) Escaped() {
+// Escaped updates the member when they successfully escape during a heist. The loot is the amount
+// of credits the member received from the heist.
+func (member *HeistMember) Escaped(loot int) {
 	log.Trace("--> heist.Member.Escaped")
 	log.Trace("<-- heist.Member.Escaped")
 
 	member.Spree++
+	member.LongestSpree = max(member.LongestSpree, member.Spree)
+	member.TotalHeists++
+	member.Successful++
+	member.TotalStolen += loot
 	writeMember(member)
 
 	log.WithFields(log.Fields{"guild": member.GuildID, "member": member.MemberID}).Debug("escaped from jail")
@@ -0,0 +1,57 @@
+package heist
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HeistRecord is the persisted outcome of a heist.
+type HeistRecord struct {
+	ID          primitive.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID     string               `json:"guild_id" bson:"guild_id"`
+	Target      string               `json:"target" bson:"target"`
+	OrganizerID string               `json:"organizer_id" bson:"organizer_id"`
+	StartTime   time.Time            `json:"start_time" bson:"start_time"`
+	EndTime     time.Time            `json:"end_time" bson:"end_time"`
+	TotalStolen int                  `json:"total_stolen" bson:"total_stolen"`
+	Crew        []*HeistRecordMember `json:"crew" bson:"crew"`
+}
+
+// HeistRecordMember is the outcome of a heist for a single member of the crew.
+type HeistRecordMember struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	Status   string `json:"status" bson:"status"`
+	Stolen   int    `json:"stolen" bson:"stolen"`
+	Bonus    int    `json:"bonus" bson:"bonus"`
+}
+
+// newHeistRecord creates the record of a heist from its results.
+func newHeistRecord(res *HeistResult) *HeistRecord {
+	log.Trace("--> heist.newHeistRecord")
+	defer log.Trace("<-- heist.newHeistRecord")
+
+	record := &HeistRecord{
+		GuildID:     res.heist.GuildID,
+		Target:      res.Target.Name,
+		OrganizerID: res.heist.Organizer.MemberID,
+		StartTime:   res.heist.StartTime,
+		EndTime:     time.Now(),
+		TotalStolen: res.TotalStolen,
+		Crew:        make([]*HeistRecordMember, 0, len(res.AllResults)),
+	}
+	for _, result := range res.AllResults {
+		member := &HeistRecordMember{
+			MemberID: result.Player.MemberID,
+			Status:   result.Status,
+		}
+		if len(res.Escaped) > 0 {
+			member.Stolen = result.StolenCredits
+			member.Bonus = result.BonusCredits
+		}
+		record.Crew = append(record.Crew, member)
+	}
+
+	return record
+}
@@ -0,0 +1,155 @@
+package heist
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/internal/channel"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/language"
+)
+
+// Stages a heist goes through. These are saved so that, if the bot is restarted, it
+// is known whether the crew still needs to be refunded.
+const (
+	HEIST_PLANNING = "planning"
+	HEIST_STARTED  = "started"
+	HEIST_COMPLETE = "complete"
+)
+
+// HeistState is the saved state of a heist that is being planned or is in progress. It is used
+// to clean up after a heist if the bot is restarted before the heist ends.
+type HeistState struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID     string             `json:"guild_id" bson:"guild_id"`
+	ChannelID   string             `json:"channel_id" bson:"channel_id"`
+	MessageID   string             `json:"message_id" bson:"message_id"`
+	OrganizerID string             `json:"organizer_id" bson:"organizer_id"`
+	CrewIDs     []string           `json:"crew_ids" bson:"crew_ids"`
+	HeistCost   int                `json:"heist_cost" bson:"heist_cost"`
+	Stage       string             `json:"stage" bson:"stage"`
+	StartTime   time.Time          `json:"start_time" bson:"start_time"`
+	Mute        *channel.MuteState `json:"mute,omitempty" bson:"mute,omitempty"`
+}
+
+// saveState saves the current state of the heist to the database.
+func (h *Heist) saveState() {
+	log.Trace("--> heist.Heist.saveState")
+	defer log.Trace("<-- heist.Heist.saveState")
+
+	h.mutex.Lock()
+	state := &HeistState{
+		GuildID:     h.GuildID,
+		ChannelID:   h.channelID,
+		MessageID:   h.messageID,
+		OrganizerID: h.Organizer.MemberID,
+		CrewIDs:     make([]string, 0, len(h.Crew)),
+		HeistCost:   h.config.HeistCost,
+		Stage:       h.stage,
+		StartTime:   h.StartTime,
+	}
+	for _, crewMember := range h.Crew {
+		state.CrewIDs = append(state.CrewIDs, crewMember.MemberID)
+	}
+	if h.mute != nil {
+		state.Mute = h.mute.GetState()
+	}
+	h.mutex.Unlock()
+
+	writeHeistState(state)
+}
+
+// setStage updates the stage of the heist and saves the heist state to the database.
+func (h *Heist) setStage(stage string) {
+	log.Trace("--> heist.Heist.setStage")
+	defer log.Trace("<-- heist.Heist.setStage")
+
+	h.mutex.Lock()
+	h.stage = stage
+	h.mutex.Unlock()
+
+	h.saveState()
+	log.WithFields(log.Fields{"guild": h.GuildID, "stage": stage}).Debug("set heist stage")
+}
+
+// recoverHeists cleans up any heists that were being planned or were in progress when the bot
+// was last stopped. The crew is refunded the cost of the heist, the channel is un-muted, and
+// the channel is told what happened.
+func recoverHeists(s *discordgo.Session) {
+	log.Trace("--> heist.recoverHeists")
+	defer log.Trace("<-- heist.recoverHeists")
+
+	states, err := readHeistStates()
+	if err != nil {
+		log.WithField("error", err).Error("unable to read the saved heist states")
+		return
+	}
+
+	for _, state := range states {
+		recoverHeist(s, state)
+	}
+}
+
+// recoverHeist cleans up a single heist that was interrupted by the bot being restarted.
+func recoverHeist(s *discordgo.Session, state *HeistState) {
+	log.Trace("--> heist.recoverHeist")
+	defer log.Trace("<-- heist.recoverHeist")
+
+	theme := GetTheme(state.GuildID)
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	refunded := state.Stage != HEIST_COMPLETE
+	if refunded {
+		for _, memberID := range state.CrewIDs {
+			account := bank.GetAccount(state.GuildID, memberID)
+			account.Deposit(state.HeistCost)
+		}
+		log.WithFields(log.Fields{"guild": state.GuildID, "crew": len(state.CrewIDs), "cost": state.HeistCost}).Info("refunded crew for interrupted heist")
+	}
+
+	if state.Mute != nil {
+		mute := channel.RestoreChannelMute(s, state.Mute)
+		mute.UnmuteChannel()
+	}
+
+	if state.ChannelID != "" {
+		if state.MessageID != "" {
+			components := []discordgo.MessageComponent{}
+			_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ID:         state.MessageID,
+				Channel:    state.ChannelID,
+				Components: &components,
+			})
+			if err != nil {
+				log.WithFields(log.Fields{"guild": state.GuildID, "error": err}).Warn("unable to remove the buttons from the heist message")
+			}
+		}
+
+		var msg string
+		if refunded {
+			msg = p.Sprintf("The bot was restarted while a %s was in progress. The %s has been cancelled and the %s of %d has been refunded %d credits each.",
+				theme.Heist,
+				theme.Heist,
+				theme.Crew,
+				len(state.CrewIDs),
+				state.HeistCost,
+			)
+		} else {
+			msg = fmt.Sprintf("The bot was restarted while the results of the %s were being distributed. The %s has been ended.",
+				theme.Heist,
+				theme.Heist,
+			)
+		}
+		_, err := s.ChannelMessageSend(state.ChannelID, msg)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": state.GuildID, "error": err}).Warn("unable to report the interrupted heist to the channel")
+		}
+	}
+
+	deleteHeistState(state.GuildID)
+	log.WithFields(log.Fields{"guild": state.GuildID, "stage": state.Stage}).Info("recovered interrupted heist")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/rbrabson/goblin/guild"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -155,7 +156,15 @@ func vaultUpdater() {
 	for {
 		time.Sleep(timer)
 		log.WithFields(log.Fields{"timer": timer}).Trace("vault updater")
+		enabled := make(map[string]bool)
 		for _, target := range getAllTargets(filter) {
+			// Vaults don't recover in guilds where heists are disabled
+			if _, ok := enabled[target.GuildID]; !ok {
+				enabled[target.GuildID] = guild.IsPluginEnabled(target.GuildID, PLUGIN_NAME)
+			}
+			if !enabled[target.GuildID] {
+				continue
+			}
 			recoverAmount := int(float64(target.VaultMax) * VAULT_RECOVER_PERCENT)
 			newVaultAmount := min(target.Vault+recoverAmount, target.VaultMax)
 			log.WithFields(log.Fields{"guild": target.GuildID, "target": target.Name, "old": target.Vault, "new": newVaultAmount, "max": target.VaultMax}).Info("vault updater: update vault")
@@ -1,108 +1,1197 @@
 package race
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
 	"github.com/rbrabson/goblin/internal/discmsg"
+	"github.com/rbrabson/goblin/internal/format"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/text/language"
 )
 
+const (
+	RACE_HISTORY_SIZE = 10 // Number of races listed by `/race history`
+	MAX_BET_BUTTONS   = 11 // Number of racers that may be bet on, each of which has a button on the race message
+)
+
 var (
+	minHouseCut = float64(1) // A cut of 0 is treated as unset, and replaced by the default
+	maxHouseCut = float64(100)
+
 	componentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
 		"join_race":       joinRace,
-		"race_bet_one":    betOnRace,
-		"race_bet_two":    betOnRace,
-		"race_bet_three":  betOnRace,
-		"race_bet_four":   betOnRace,
-		"race_bet_five":   betOnRace,
-		"race_bet_six":    betOnRace,
-		"race_bet_seven":  betOnRace,
-		"race_bet_eight":  betOnRace,
-		"race_bet_nine":   betOnRace,
-		"race_bet_ten":    betOnRace,
-		"race_bet_eleven": betOnRace,
+		"race_bet":        betOnRace,
+		"race_bet_amount": placeBet,
 	}
 
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"race":       race,
-		"race-admin": admin,
-	}
-
-	memberCommands = []*discordgo.ApplicationCommand{
-		{
+	memberRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "race",
 			Description: "Race game commands.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "start",
 					Description: "Starts a new race.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     startRace,
 				},
 				{
 					Name:        "stats",
 					Description: "Returns the race stats for the player.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     raceStats,
+				},
+				{
+					Name:        "racer",
+					Description: "Commands for the racer you own.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "buy",
+							Description: "Buys your own racer to use in races.",
+							Handler:     buyRacer,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of your racer.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "emoji",
+									Description: "The emoji of one of the current theme's racers. A random one is chosen if not given.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "train",
+							Description: "Trains your racer to improve how it moves.",
+							Handler:     trainRacer,
+						},
+					},
+				},
+				{
+					Name:        "history",
+					Description: "Lists the most recent races.",
+					Handler:     raceHistory,
+				},
+				{
+					Name:        "replay",
+					Description: "Replays a past race.",
+					Handler:     replayRace,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "id",
+							Description: "The number of the race to replay.",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Commands for race tournaments.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "join",
+							Description: "Registers for the tournament that is open for registration.",
+							Handler:     joinTournament,
+						},
+						{
+							Name:        "bracket",
+							Description: "Shows the bracket for the current tournament.",
+							Handler:     tournamentBracket,
+						},
+					},
 				},
 			},
 		},
-	}
+	)
 
-	adminCommands = []*discordgo.ApplicationCommand{
-		{
+	adminRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "race-admin",
 			Description: "Race game admin commands.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "reset",
 					Description: "Resets a hung race.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     resetRace,
+					Permission:  guild.PermissionModerator,
+				},
+				{
+					Name:        "profile",
+					Description: "Commands that manage the movement profiles for racers.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "list",
+							Description: "Lists the movement profiles for the current race theme.",
+							Handler:     listProfiles,
+						},
+						{
+							Name:        "set",
+							Description: "Creates or updates a movement profile for the current race theme.",
+							Handler:     setProfile,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the movement profile.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The minimum roll of the die each turn.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum roll of the die each turn.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "multiplier",
+									Description: "The amount the roll is multiplied by to get the distance moved. Defaults to 1.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "opening",
+									Description: "Comma-separated distances moved on the first turns of the race.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "rest",
+									Description: "The racer doesn't move every this many turns.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "burst_chance",
+									Description: "The percent chance of a burst of speed each turn.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "burst_movement",
+									Description: "The distance moved during a burst of speed.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "remove",
+							Description: "Removes a movement profile from the current race theme.",
+							Handler:     removeProfile,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the movement profile.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "config",
+					Description: "Configures the race game.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "info",
+							Description: "Returns the configuration information for the server.",
+							Handler:     configInfo,
+						},
+						{
+							Name:        "bet",
+							Description: "Sets the minimum amount that may be bet on a racer.",
+							Handler:     configBet,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "amount",
+									Description: "The minimum amount that may be bet on a racer.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "costs",
+							Description: "Sets the cost to buy and train a racer.",
+							Handler:     configCosts,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "racer",
+									Description: "The cost to buy a racer.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "training",
+									Description: "The cost of the first level of training, which increases with each level.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "house",
+							Description: "Sets the percent of the betting pool kept by the house.",
+							Handler:     configHouse,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "cut",
+									Description: "The percent of the betting pool kept by the house.",
+									Required:    true,
+									MinValue:    &minHouseCut,
+									MaxValue:    maxHouseCut,
+								},
+							},
+						},
+						{
+							Name:        "entry",
+							Description: "Sets the fee to join a race.",
+							Handler:     configEntry,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "fee",
+									Description: "The fee to join a race.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "racers",
+							Description: "Sets the minimum and maximum number of racers in a race.",
+							Handler:     configRacers,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The minimum number of racers needed to start a race.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum number of racers that may join a race.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "prize",
+							Description: "Sets the range of the prize paid to the winner of a race.",
+							Handler:     configPrize,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The minimum prize.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum prize.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "wait",
+							Description: "Sets how long to wait during each part of a race.",
+							Handler:     configWait,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "start",
+									Description: "The time to wait for racers to join a race, in seconds.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "bets",
+									Description: "The time to wait for bets to be placed, in seconds.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "between",
+									Description: "The time to wait between races, in seconds.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "lines",
+							Description: "Sets the starting and ending lines of the race track.",
+							Handler:     configLines,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "start",
+									Description: "The starting line of the race track.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "end",
+									Description: "The ending line of the race track.",
+									Required:    false,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "racer",
+					Description: "Commands that manage the racers for the current race theme.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "list",
+							Description: "Lists the racers for the current race theme.",
+							Handler:     listRacers,
+						},
+						{
+							Name:        "add",
+							Description: "Adds a racer to the current race theme.",
+							Handler:     addThemeRacer,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "emoji",
+									Description: "The emoji used for the racer.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "movement",
+									Description: "The name of the movement profile used by the racer.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "remove",
+							Description: "Removes a racer from the current race theme.",
+							Handler:     removeThemeRacer,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "emoji",
+									Description: "The emoji used for the racer.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Commands that manage race tournaments.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "open",
+							Description: "Opens registration for a new tournament in this channel.",
+							Handler:     openTournament,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the tournament.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "prize",
+									Description: "The prize paid to the champion, in addition to the entry fees.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "entry_fee",
+									Description: "The fee to register for the tournament. Defaults to 0.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "heat_size",
+									Description: "The number of racers in each heat. Defaults to 6.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "advance",
+									Description: "The number of racers in each heat who advance to the next round. Defaults to 2.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "interval",
+									Description: "The time between rounds, in minutes. Defaults to 10.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role given to the champion.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "start",
+							Description: "Closes registration and starts running the tournament.",
+							Handler:     startTournament,
+						},
+						{
+							Name:        "cancel",
+							Description: "Cancels the tournament and refunds the entry fees.",
+							Handler:     cancelTournament,
+						},
+					},
+				},
+				{
+					Name:        "theme",
+					Description: "Commands that manage the race themes.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "list",
+							Description: "Lists the available race themes.",
+							Handler:     listThemes,
+						},
+						{
+							Name:        "create",
+							Description: "Creates a new race theme.",
+							Handler:     createTheme,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the theme to create.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "set",
+							Description: "Sets the current race theme.",
+							Handler:     setTheme,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the theme to set.",
+									Required:    true,
+								},
+							},
+						},
+					},
 				},
 			},
 		},
-	}
+	)
 )
 
-// admin routes various `race-admin` subcommands to the appropriate handlers.
-func admin(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> race.admin")
-	defer log.Trace("<-- race.admin")
+// resetRace resets a hung race.
+func resetRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.resetRace")
+	defer log.Trace("<-- race.resetRace")
 
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "reset":
-		resetRace(s, i)
-	default:
-		log.WithFields(log.Fields{"guild_id": i.GuildID, "user_id": i.Member.User.ID, "command": options[0].Name}).Error("unknown command")
-		discmsg.SendEphemeralResponse(s, i, "Command is unknown")
+	ResetRace(i.GuildID)
+	discmsg.SendEphemeralResponse(s, i, "Race has been reset")
+
+}
+
+// listProfiles lists the movement profiles available for the current race theme.
+func listProfiles(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.listProfiles")
+	defer log.Trace("<-- race.listProfiles")
+
+	config := GetConfig(i.GuildID)
+	profiles := GetMovementProfiles(i.GuildID, config.Theme)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
 	}
+	slices.Sort(names)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Movement profiles for the %s theme:\n", config.Theme))
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", name, profiles[name]))
+	}
+	discmsg.SendEphemeralResponse(s, i, sb.String())
 }
 
-// race routes the various `race` subcommands to the appropriate handlers.
-func race(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> race.race")
-	defer log.Trace("<-- race.race")
+// setProfile creates or updates a movement profile for the current race theme.
+func setProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.setProfile")
+	defer log.Trace("<-- race.setProfile")
 
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "start":
-		startRace(s, i)
-	case "stats":
-		raceStats(s, i)
-	default:
-		discmsg.SendEphemeralResponse(s, i, "Command is unknown")
-		log.WithFields(log.Fields{"guild_id": i.GuildID, "user_id": i.Member.User.ID, "command": options[0].Name}).Error("unknown command")
+	config := GetConfig(i.GuildID)
+	profile := &MovementProfile{
+		GuildID:    i.GuildID,
+		Theme:      config.Theme,
+		Multiplier: 1,
+	}
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "name":
+			profile.Name = strings.ToLower(strings.TrimSpace(option.StringValue()))
+		case "min":
+			profile.MinRoll = int(option.IntValue())
+		case "max":
+			profile.MaxRoll = int(option.IntValue())
+		case "multiplier":
+			profile.Multiplier = int(option.IntValue())
+		case "opening":
+			moves, err := parseOpeningMoves(option.StringValue())
+			if err != nil {
+				discmsg.SendEphemeralResponse(s, i, err.Error())
+				return
+			}
+			profile.OpeningMoves = moves
+		case "rest":
+			profile.RestEvery = int(option.IntValue())
+		case "burst_chance":
+			profile.BurstChance = int(option.IntValue())
+		case "burst_movement":
+			profile.BurstMovement = int(option.IntValue())
+		}
 	}
+
+	err := NewMovementProfile(profile)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("Movement profile **%s** set to %s", profile.Name, profile))
 }
 
-// resetRace resets a hung race.
-func resetRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> race.resetRace")
-	defer log.Trace("<-- race.resetRace")
+// removeProfile removes a movement profile from the current race theme.
+func removeProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.removeProfile")
+	defer log.Trace("<-- race.removeProfile")
 
-	ResetRace(i.GuildID)
-	discmsg.SendEphemeralResponse(s, i, "Race has been reset")
+	config := GetConfig(i.GuildID)
+	name := strings.ToLower(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	err := RemoveMovementProfile(i.GuildID, config.Theme, name)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	msg := fmt.Sprintf("Movement profile **%s** removed", name)
+	if _, ok := builtinProfiles[name]; ok {
+		msg += ". The built-in profile will be used instead"
+	}
+	discmsg.SendResponse(s, i, msg)
+}
+
+// listRacers lists the racers for the current race theme.
+func listRacers(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.listRacers")
+	defer log.Trace("<-- race.listRacers")
+
+	config := GetConfig(i.GuildID)
+	racers := GetRacers(i.GuildID, config.Theme)
+	if len(racers) == 0 {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("The %s theme has no racers.", config.Theme))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Racers for the %s theme:\n", config.Theme))
+	for _, racer := range racers {
+		sb.WriteString(fmt.Sprintf("%s %s\n", racer.Emoji, racer.MovementSpeed))
+	}
+	discmsg.SendEphemeralResponse(s, i, sb.String())
+}
+
+// addThemeRacer adds a racer to the current race theme.
+func addThemeRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.addThemeRacer")
+	defer log.Trace("<-- race.addThemeRacer")
+
+	var emoji, movement string
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "emoji":
+			emoji = strings.TrimSpace(option.StringValue())
+		case "movement":
+			movement = strings.ToLower(strings.TrimSpace(option.StringValue()))
+		}
+	}
+
+	config := GetConfig(i.GuildID)
+	_, err := NewRacer(i.GuildID, config.Theme, emoji, movement)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("Racer %s added to the %s theme with the %s movement profile", emoji, config.Theme, movement))
+}
+
+// removeThemeRacer removes a racer from the current race theme.
+func removeThemeRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.removeThemeRacer")
+	defer log.Trace("<-- race.removeThemeRacer")
+
+	emoji := strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue())
+	config := GetConfig(i.GuildID)
+	err := RemoveRacer(i.GuildID, config.Theme, emoji)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("Racer %s removed from the %s theme", emoji, config.Theme))
+}
+
+// listThemes lists the race themes available to the guild.
+func listThemes(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.listThemes")
+	defer log.Trace("<-- race.listThemes")
+
+	config := GetConfig(i.GuildID)
+	themes := GetThemeNames(i.GuildID)
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Type:        discordgo.EmbedTypeRich,
+			Title:       "Available Themes",
+			Description: "Available Themes for the Race bot",
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:   "Themes",
+					Value:  strings.Join(themes, ", "),
+					Inline: true,
+				},
+				{
+					Name:   "Current",
+					Value:  config.Theme,
+					Inline: true,
+				},
+			},
+		},
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: embeds,
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the list of race themes")
+	}
+}
+
+// createTheme creates a new race theme.
+func createTheme(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.createTheme")
+	defer log.Trace("<-- race.createTheme")
+
+	name := strings.ToLower(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	_, err := NewTheme(i.GuildID, name)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("Theme %s created. Set it as the current theme and use `/race-admin racer add` to add racers to it.", name))
+}
+
+// setTheme sets the race theme to the one specified in the command.
+func setTheme(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.setTheme")
+	defer log.Trace("<-- race.setTheme")
+
+	name := strings.ToLower(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	err := SetTheme(i.GuildID, name)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("Theme %s is now being used.", name))
+}
+
+// configBet sets the minimum amount that may be bet on a racer.
+func configBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configBet")
+	defer log.Trace("<-- race.configBet")
+
+	amount := i.ApplicationCommandData().Options[0].Options[0].Options[0].IntValue()
+	if amount < 1 {
+		discmsg.SendEphemeralResponse(s, i, "The bet amount must be at least 1")
+		return
+	}
+	config := GetConfig(i.GuildID)
+	config.BetAmount = amount
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Minimum bet set to %d", amount))
+	writeConfig(config)
+}
+
+// configCosts sets the cost to buy and train a racer.
+func configCosts(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configCosts")
+	defer log.Trace("<-- race.configCosts")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		if option.IntValue() < 1 {
+			discmsg.SendEphemeralResponse(s, i, "Costs must be at least 1")
+			return
+		}
+		switch option.Name {
+		case "racer":
+			config.RacerCost = int(option.IntValue())
+		case "training":
+			config.TrainingCost = int(option.IntValue())
+		}
+	}
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Racers cost %d to buy and %d to train", config.RacerCost, config.TrainingCost))
+	writeConfig(config)
+}
+
+// configHouse sets the percent of the betting pool kept by the house.
+func configHouse(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configHouse")
+	defer log.Trace("<-- race.configHouse")
+
+	cut := i.ApplicationCommandData().Options[0].Options[0].Options[0].IntValue()
+	if cut < int64(minHouseCut) || cut > int64(maxHouseCut) {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("The house cut must be between %d and %d", int(minHouseCut), int(maxHouseCut)))
+		return
+	}
+	config := GetConfig(i.GuildID)
+	config.HouseCut = int(cut)
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("House cut set to %d%%", cut))
+	writeConfig(config)
+}
+
+// configEntry sets the fee to join a race.
+func configEntry(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configEntry")
+	defer log.Trace("<-- race.configEntry")
+
+	fee := i.ApplicationCommandData().Options[0].Options[0].Options[0].IntValue()
+	if fee < 0 {
+		discmsg.SendEphemeralResponse(s, i, "The entry fee may not be negative")
+		return
+	}
+	config := GetConfig(i.GuildID)
+	config.EntryFee = int(fee)
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Entry fee set to %d", fee))
+	writeConfig(config)
+}
+
+// configRacers sets the minimum and maximum number of racers in a race.
+func configRacers(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configRacers")
+	defer log.Trace("<-- race.configRacers")
+
+	config := GetConfig(i.GuildID)
+	minRacers := config.MinNumRacers
+	maxRacers := config.MaxNumRacers
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "min":
+			minRacers = int(option.IntValue())
+		case "max":
+			maxRacers = int(option.IntValue())
+		}
+	}
+	if minRacers < 1 {
+		discmsg.SendEphemeralResponse(s, i, "The minimum number of racers must be at least 1")
+		return
+	}
+	if maxRacers < minRacers || maxRacers > MAX_BET_BUTTONS {
+		discmsg.SendEphemeralResponse(s, i, fmt.Sprintf("The maximum number of racers must be between %d and %d", minRacers, MAX_BET_BUTTONS))
+		return
+	}
+	config.MinNumRacers = minRacers
+	config.MaxNumRacers = maxRacers
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Number of racers set to a minimum of %d and a maximum of %d", minRacers, maxRacers))
+	writeConfig(config)
+}
+
+// configPrize sets the range of the prize paid to the winner of a race.
+func configPrize(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configPrize")
+	defer log.Trace("<-- race.configPrize")
+
+	config := GetConfig(i.GuildID)
+	minPrize := config.MinPriceAmount
+	maxPrize := config.MaxPrizeAmount
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "min":
+			minPrize = int(option.IntValue())
+		case "max":
+			maxPrize = int(option.IntValue())
+		}
+	}
+	if minPrize < 0 || maxPrize < minPrize {
+		discmsg.SendEphemeralResponse(s, i, "The minimum prize must be at least 0 and no more than the maximum prize")
+		return
+	}
+	config.MinPriceAmount = minPrize
+	config.MaxPrizeAmount = maxPrize
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Prize set to between %d and %d", minPrize, maxPrize))
+	writeConfig(config)
+}
+
+// configWait sets how long to wait for racers to join, for bets to be placed, and between races.
+func configWait(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configWait")
+	defer log.Trace("<-- race.configWait")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		if option.IntValue() < 0 {
+			discmsg.SendEphemeralResponse(s, i, "Wait times may not be negative")
+			return
+		}
+	}
+	for _, option := range options {
+		wait := time.Duration(option.IntValue()) * time.Second
+		switch option.Name {
+		case "start":
+			config.WaitToStart = wait
+		case "bets":
+			config.WaitForBets = wait
+		case "between":
+			config.WaitBetweenRaces = wait
+		}
+	}
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Wait times set to %s to join, %s for bets, and %s between races",
+		format.Duration(config.WaitToStart),
+		format.Duration(config.WaitForBets),
+		format.Duration(config.WaitBetweenRaces),
+	))
+	writeConfig(config)
+}
+
+// configLines sets the starting and ending lines of the race track.
+func configLines(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configLines")
+	defer log.Trace("<-- race.configLines")
+
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "start":
+			config.StartingLine = strings.TrimSpace(option.StringValue())
+		case "end":
+			config.EndingLine = strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	discmsg.SendResponse(s, i, fmt.Sprintf("Track set to %s%s%s", config.StartingLine, strings.Repeat(TRACK_SEGMENT, TRACK_SEGMENTS), config.EndingLine))
+	writeConfig(config)
+}
+
+// configInfo returns the configuration for the race game on this server.
+func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.configInfo")
+	defer log.Trace("<-- race.configInfo")
+
+	config := GetConfig(i.GuildID)
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	embed := &discordgo.MessageEmbed{
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "theme",
+				Value:  config.Theme,
+				Inline: true,
+			},
+			{
+				Name:   "bet",
+				Value:  p.Sprintf("%d", config.BetAmount),
+				Inline: true,
+			},
+			{
+				Name:   "entry",
+				Value:  p.Sprintf("%d", config.EntryFee),
+				Inline: true,
+			},
+			{
+				Name:   "house",
+				Value:  fmt.Sprintf("%d%%", config.HouseCut),
+				Inline: true,
+			},
+			{
+				Name:   "costs",
+				Value:  p.Sprintf("%d racer, %d training", config.RacerCost, config.TrainingCost),
+				Inline: true,
+			},
+			{
+				Name:   "racers",
+				Value:  fmt.Sprintf("%d-%d", config.MinNumRacers, config.MaxNumRacers),
+				Inline: true,
+			},
+			{
+				Name:   "prize",
+				Value:  p.Sprintf("%d-%d", config.MinPriceAmount, config.MaxPrizeAmount),
+				Inline: true,
+			},
+			{
+				Name:   "wait",
+				Value:  fmt.Sprintf("%s to join, %s for bets, %s between races", format.Duration(config.WaitToStart), format.Duration(config.WaitForBets), format.Duration(config.WaitBetweenRaces)),
+				Inline: false,
+			},
+			{
+				Name:   "lines",
+				Value:  fmt.Sprintf("%s%s%s", config.StartingLine, strings.Repeat(TRACK_SEGMENT, TRACK_SEGMENTS), config.EndingLine),
+				Inline: false,
+			},
+		},
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Title:  "Race Configuration",
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the race configuration")
+	}
+}
+
+// buyRacer buys a racer for the member.
+func buyRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.buyRacer")
+	defer log.Trace("<-- race.buyRacer")
+
+	var name, emoji string
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "name":
+			name = strings.TrimSpace(option.StringValue())
+		case "emoji":
+			emoji = strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	owned, err := BuyRacer(i.GuildID, i.Member.User.ID, name, emoji)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	config := GetConfig(i.GuildID)
+	discmsg.SendEphemeralResponse(s, i, p.Sprintf("You bought %s %s for %d credits. You will race with %s from now on.", owned.Emoji, owned.Name, config.RacerCost, owned.Name))
+}
+
+// trainRacer trains the member's racer.
+func trainRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.trainRacer")
+	defer log.Trace("<-- race.trainRacer")
+
+	owned := GetOwnedRacer(i.GuildID, i.Member.User.ID)
+	if owned == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoOwnedRacer.Error())
+		return
+	}
+	config := GetConfig(i.GuildID)
+	cost := owned.TrainingCost(config)
+	err := owned.Train(config)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	discmsg.SendEphemeralResponse(s, i, p.Sprintf("You spent %d credits training %s, which now has %d of %d levels of training.", cost, owned.Name, owned.Training, MAX_TRAINING))
+}
+
+// openTournament opens registration for a new tournament.
+func openTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.openTournament")
+	defer log.Trace("<-- race.openTournament")
+
+	options := &TournamentOptions{
+		HeatSize: TOURNAMENT_HEAT_SIZE,
+		Advance:  TOURNAMENT_ADVANCE,
+		Interval: TOURNAMENT_INTERVAL,
+	}
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "name":
+			options.Name = strings.TrimSpace(option.StringValue())
+		case "prize":
+			options.Prize = int(option.IntValue())
+		case "entry_fee":
+			options.EntryFee = int(option.IntValue())
+		case "heat_size":
+			options.HeatSize = int(option.IntValue())
+		case "advance":
+			options.Advance = int(option.IntValue())
+		case "interval":
+			options.Interval = time.Duration(option.IntValue()) * time.Minute
+		case "role":
+			options.RoleID = option.RoleValue(s, i.GuildID).ID
+		}
+	}
+
+	t, err := OpenTournament(i.GuildID, i.ChannelID, options)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	msg := p.Sprintf("Registration for the %s tournament (season %d) is open! Use `/race tournament join` to register", t.Name, t.Season)
+	if t.EntryFee > 0 {
+		msg += p.Sprintf(" for %d credits", t.EntryFee)
+	}
+	msg += p.Sprintf(". The champion wins the prize pool, which starts at %d credits.", t.PrizePool)
+	discmsg.SendResponse(s, i, msg)
+}
+
+// startTournament closes registration and starts running the tournament.
+func startTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.startTournament")
+	defer log.Trace("<-- race.startTournament")
+
+	t := GetTournament(i.GuildID)
+	if t == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoTournament.Error())
+		return
+	}
+	err := t.Start(s)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	discmsg.SendResponse(s, i, p.Sprintf("Registration for the %s tournament is closed. %d racers have been seeded into %d heats, and the first round is starting.",
+		t.Name,
+		len(t.EntrantIDs),
+		len(t.currentRound().Heats),
+	))
+}
+
+// cancelTournament cancels the current tournament.
+func cancelTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.cancelTournament")
+	defer log.Trace("<-- race.cancelTournament")
+
+	t := GetTournament(i.GuildID)
+	if t == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoTournament.Error())
+		return
+	}
+	err := t.Cancel()
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, fmt.Sprintf("The %s tournament has been cancelled and any entry fees refunded.", t.Name))
+}
+
+// joinTournament registers the member for the tournament.
+func joinTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.joinTournament")
+	defer log.Trace("<-- race.joinTournament")
+
+	t := GetTournament(i.GuildID)
+	if t == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoTournament.Error())
+		return
+	}
+	err := t.Register(i.Member.User.ID)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	discmsg.SendEphemeralResponse(s, i, p.Sprintf("You are registered for the %s tournament. %d racers have registered, and the prize pool is %d credits.", t.Name, len(t.EntrantIDs), t.PrizePool))
+}
+
+// tournamentBracket shows the bracket for the current tournament.
+func tournamentBracket(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.tournamentBracket")
+	defer log.Trace("<-- race.tournamentBracket")
+
+	t := GetTournament(i.GuildID)
+	if t == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoTournament.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	var description string
+	switch t.Stage {
+	case TOURNAMENT_REGISTRATION:
+		description = p.Sprintf("Registration is open. %d racers have registered.", len(t.EntrantIDs))
+	default:
+		description = p.Sprintf("Round %d starts %s.", t.currentRound().Number, discordTimestamp(t.NextRound))
+	}
+	embed := &discordgo.MessageEmbed{
+		Type:        discordgo.EmbedTypeRich,
+		Title:       p.Sprintf("%s (Season %d)", t.Name, t.Season),
+		Description: description,
+		Fields:      renderBracket(t),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: p.Sprintf("Prize pool: %d credits", t.PrizePool),
+		},
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the tournament bracket")
+	}
+}
 
+// discordTimestamp returns the time formatted so that Discord shows it relative to the current time.
+func discordTimestamp(t time.Time) string {
+	return fmt.Sprintf("<t:%d:R>", t.Unix())
 }
 
 // startRace starts a race that other members may join.
@@ -110,8 +1199,51 @@ func startRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> race.startRace")
 	defer log.Trace("<-- race.startRace")
 
-	discmsg.SendEphemeralResponse(s, i, "start not implemented")
-	// TODO: implement
+	race, err := NewRace(i.GuildID)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Debug("unable to start the race")
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	discmsg.SendResponse(s, i, "Starting a race...")
+	race.interaction = i
+
+	// The member starting the race is the first one to join it.
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID)
+	err = addRacer(race, guildMember)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "member": guildMember.MemberID, "error": err}).Debug("unable to join the race")
+		discmsg.EditResponse(s, i, err.Error())
+		ResetRace(i.GuildID)
+		return
+	}
+
+	raceMessage(s, race, "join")
+	waitOnRace(s, race, race.config.WaitToStart, "join")
+
+	if len(race.Racers) < race.config.MinNumRacers {
+		raceMessage(s, race, "cancel")
+		refundEntryFees(race)
+		p := discmsg.GetPrinter(language.AmericanEnglish)
+		msg := p.Sprintf("The race was cancelled as not enough racers joined.")
+		s.ChannelMessageSend(i.ChannelID, msg)
+		log.WithFields(log.Fields{"guild": i.GuildID, "racers": len(race.Racers)}).Info("race cancelled due to lack of racers")
+		ResetRace(i.GuildID)
+		return
+	}
+	defer race.End()
+
+	race.betsClose = time.Now().Add(race.config.WaitForBets)
+	race.setStage(RACE_BETTING)
+	raceMessage(s, race, "bet")
+	waitOnRace(s, race, race.config.WaitForBets, "bet")
+
+	race.setStage(RACE_STARTED)
+	raceMessage(s, race, "start")
+	race.RunRace(TRACK_LENGTH)
+	animateRace(s, race, TRACK_LENGTH)
+
+	sendRaceResults(s, race)
 }
 
 // joinRace attempts to join a race that is getting ready to start.
@@ -119,8 +1251,83 @@ func joinRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> race.joinRace")
 	defer log.Trace("<-- race.joinRace")
 
-	discmsg.SendEphemeralResponse(s, i, "join not implemented")
-	// TODO: implement
+	raceLock.Lock()
+	race := currentRaces[i.GuildID]
+	raceLock.Unlock()
+	if race == nil {
+		discmsg.SendEphemeralResponse(s, i, ErrNoRace.Error())
+		return
+	}
+
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID)
+	err := addRacer(race, guildMember)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	var resp string
+	if race.config.EntryFee > 0 {
+		resp = p.Sprintf("You have joined the race at a cost of %d credits.", race.config.EntryFee)
+	} else {
+		resp = p.Sprintf("You have joined the race.")
+	}
+	discmsg.SendEphemeralResponse(s, i, resp)
+
+	raceMessage(s, race, "join")
+}
+
+// addRacer adds the guild member to the race, charging them the entry fee for the race.
+func addRacer(race *Race, guildMember *guild.Member) error {
+	log.Trace("--> race.addRacer")
+	defer log.Trace("<-- race.addRacer")
+
+	account := bank.GetAccount(race.GuildID, guildMember.MemberID)
+	if account.CurrentBalance < race.config.EntryFee {
+		return ErrNotEnoughCredits{CreditsNeeded: race.config.EntryFee}
+	}
+
+	raceMember := GetRaceMember(race.GuildID, guildMember.MemberID)
+	raceMember.guildMember = guildMember
+	var participant *RaceParticipant
+	owned := GetOwnedRacer(race.GuildID, guildMember.MemberID)
+	if owned != nil {
+		if rest := owned.RestRemaining(); rest > 0 {
+			return ErrRacerTired{RemainingTime: rest}
+		}
+		participant = newOwnedRaceParticipant(raceMember, owned, GetMovementProfiles(race.GuildID, race.config.Theme))
+	} else {
+		racers := GetRacers(race.GuildID, race.config.Theme)
+		if len(racers) == 0 {
+			return ErrNoRacersFound
+		}
+		participant = newRaceParitipcant(raceMember, race.availableRacers(racers))
+	}
+	err := race.AddRacer(participant)
+	if err != nil {
+		return err
+	}
+
+	if race.config.EntryFee > 0 {
+		account.WithdrawWithReason(race.config.EntryFee, "race entry fee")
+	}
+
+	return nil
+}
+
+// refundEntryFees returns the entry fee to each racer when a race is cancelled.
+func refundEntryFees(race *Race) {
+	log.Trace("--> race.refundEntryFees")
+	defer log.Trace("<-- race.refundEntryFees")
+
+	if race.config.EntryFee <= 0 {
+		return
+	}
+	for _, racer := range race.Racers {
+		account := bank.GetAccount(race.GuildID, racer.Member.MemberID)
+		account.DepositWithReason(race.config.EntryFee, "race entry fee refund")
+	}
 }
 
 // raceStats returns a players race stats.
@@ -134,8 +1341,7 @@ func raceStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 	p := discmsg.GetPrinter(lang)
 
-	// Update the member's name in the guild.
-	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.DisplayName())
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID)
 
 	raceMember := GetRaceMember(i.GuildID, i.Member.User.ID)
 
@@ -197,6 +1403,45 @@ func raceStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		},
 	}
 
+	owned := GetOwnedRacer(i.GuildID, i.Member.User.ID)
+	if owned != nil {
+		rest := "Ready to race"
+		if remaining := owned.RestRemaining(); remaining > 0 {
+			rest = "Resting for " + format.Duration(remaining)
+		}
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Type:  discordgo.EmbedTypeRich,
+			Title: p.Sprintf("%s %s", owned.Emoji, owned.Name),
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:   "Level",
+					Value:  p.Sprintf("%d (%d XP)", owned.Level(), owned.Experience),
+					Inline: true,
+				},
+				{
+					Name:   "Training",
+					Value:  p.Sprintf("%d of %d", owned.Training, MAX_TRAINING),
+					Inline: true,
+				},
+				{
+					Name:   "Wins",
+					Value:  p.Sprintf("%d of %d races", owned.Wins, owned.Races),
+					Inline: true,
+				},
+				{
+					Name:   "Fatigue",
+					Value:  p.Sprintf("%.0f/%d", owned.GetFatigue(), MAX_FATIGUE),
+					Inline: true,
+				},
+				{
+					Name:   "Status",
+					Value:  rest,
+					Inline: true,
+				},
+			},
+		})
+	}
+
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
@@ -209,17 +1454,497 @@ func raceStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
-// betOnRace processes a bet placed by a member on the race.
+// raceHistory lists the most recent races in the guild.
+func raceHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.raceHistory")
+	defer log.Trace("<-- race.raceHistory")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	records, _ := readRecentRaceRecords(i.GuildID, RACE_HISTORY_SIZE)
+	if len(records) == 0 {
+		discmsg.SendEphemeralResponse(s, i, "No races have been run yet.")
+		return
+	}
+
+	var sb strings.Builder
+	for _, record := range records {
+		winner := "no winner"
+		if w := record.Winner(); w != nil {
+			winner = p.Sprintf("%s %s", w.Emoji, w.Name)
+		}
+		sb.WriteString(p.Sprintf("**#%d** %s: won by %s (%d racers, %d bets)\n",
+			record.Number,
+			discordTimestamp(record.EndTime),
+			winner,
+			len(record.Participants),
+			len(record.Bets),
+		))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Type:        discordgo.EmbedTypeRich,
+		Title:       "Recent Races",
+		Description: sb.String(),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Use /race replay <id> to watch a race again.",
+		},
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the race history")
+	}
+}
+
+// replayRace shows a past race again in the channel.
+func replayRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> race.replayRace")
+	defer log.Trace("<-- race.replayRace")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	number := int(i.ApplicationCommandData().Options[0].Options[0].IntValue())
+	record, err := readRaceRecord(i.GuildID, number)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	msg := p.Sprintf("Replaying race #%d from %s.", record.Number, discordTimestamp(record.EndTime))
+	if w := record.Winner(); w != nil {
+		msg += p.Sprintf(" It was won by %s %s.", w.Emoji, w.Name)
+	}
+	discmsg.SendResponse(s, i, msg)
+
+	config := &Config{
+		StartingLine: record.StartingLine,
+		EndingLine:   record.EndingLine,
+	}
+	go animateLegs(s, i.GuildID, i.ChannelID, record.getLegs(), config, record.TrackLength)
+}
+
+// betOnRace asks the member how much they want to bet on the racer whose button was selected.
 func betOnRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("---> race.betOnRace")
 	defer log.Trace("<--- race.betOnRace")
 
-	discmsg.SendEphemeralResponse(s, i, "resbetet not implemented")
-	// TODO: implement
+	race, racer, err := getBetRacer(i)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: discord.CustomID("race_bet_amount", race.getID(), racer.Member.MemberID),
+			Title:    p.Sprintf("Bet on %s", racer.Member.getName()),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    "amount",
+						Label:       p.Sprintf("Amount (minimum of %d)", race.config.BetAmount),
+						Style:       discordgo.TextInputShort,
+						Placeholder: p.Sprintf("%d", race.config.BetAmount),
+						Required:    true,
+						MaxLength:   10,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to ask for the bet amount")
+	}
+}
+
+// placeBet places the bet entered by the member into the betting pool for the race.
+func placeBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("---> race.placeBet")
+	defer log.Trace("<--- race.placeBet")
+
+	race, racer, err := getBetRacer(i)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+
+	amount, err := getBetAmount(i.ModalSubmitData())
+	if err != nil || amount < int(race.config.BetAmount) {
+		discmsg.SendEphemeralResponse(s, i, ErrInvalidBet{MinimumBet: int(race.config.BetAmount)}.Error())
+		return
+	}
+
+	raceMember := race.getRaceMember(i.Member.User.ID)
+	better := newRaceBetter(raceMember, racer, amount)
+
+	account := bank.GetAccount(i.GuildID, raceMember.MemberID)
+	if account.CurrentBalance < amount {
+		discmsg.SendEphemeralResponse(s, i, ErrNotEnoughCredits{CreditsNeeded: amount}.Error())
+		return
+	}
+	err = race.AddBetter(better)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, err.Error())
+		return
+	}
+	err = raceMember.PlaceBet(amount)
+	if err != nil {
+		race.removeBetter(better)
+		log.WithFields(log.Fields{"guild": i.GuildID, "member": raceMember.MemberID, "error": err}).Error("unable to place the bet")
+		if errors.Is(err, bank.ErrInsufficentFunds) {
+			discmsg.SendEphemeralResponse(s, i, ErrNotEnoughCredits{CreditsNeeded: amount}.Error())
+		} else {
+			discmsg.SendEphemeralResponse(s, i, "Unable to place your bet")
+		}
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	resp := p.Sprintf("You have placed a %d credit bet on %s. The odds are now %s.", amount, racer.Member.getName(), race.Pool().FormatOdds(racer))
+	discmsg.SendEphemeralResponse(s, i, resp)
+
+	raceMessage(s, race, "bet")
+}
+
+// getBetRacer returns the current race and the racer being bet on, both of which are encoded in the
+// custom ID of the bet button or modal as `<raceID>:<memberID>`. An error is returned if the race is
+// no longer the current race, such as when betting from the message for a race that has finished.
+func getBetRacer(i *discordgo.InteractionCreate) (*Race, *RaceParticipant, error) {
+	raceLock.Lock()
+	race := currentRaces[i.GuildID]
+	raceLock.Unlock()
+
+	args := discord.ComponentArgs(i)
+	if race == nil || len(args) != 2 || args[0] != race.getID() {
+		return nil, nil, ErrNoRace
+	}
+	racer := race.getRacer(args[1])
+	if racer == nil {
+		return nil, nil, ErrRacerNotFound
+	}
+
+	return race, racer, nil
+}
+
+// getBetAmount returns the amount entered in the modal used to bet on a racer.
+func getBetAmount(data discordgo.ModalSubmitInteractionData) (int, error) {
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			input, ok := rowComponent.(*discordgo.TextInput)
+			if ok && input.CustomID == "amount" {
+				return strconv.Atoi(strings.TrimSpace(input.Value))
+			}
+		}
+	}
+	return 0, ErrInvalidBet{}
 }
 
-// waitOnRace waits for racers to join the race, or betters to bet on the race.
-func waitOnRace() {
+// waitOnRace waits for racers to join the race, or betters to bet on the race. The race
+// message is periodically updated to show the time remaining.
+func waitOnRace(s *discordgo.Session, race *Race, waitTime time.Duration, action string) {
 	log.Trace("--> race.waitOnRace")
 	defer log.Trace("<-- race.waitOnRace")
+
+	endTime := time.Now().Add(waitTime)
+	for time.Now().Before(endTime) {
+		timeToWait := min(time.Until(endTime), 5*time.Second)
+		if timeToWait <= 0 {
+			break
+		}
+		time.Sleep(timeToWait)
+		raceMessage(s, race, action, endTime)
+	}
+}
+
+// raceMessage updates the message used to join and bet on the race.
+func raceMessage(s *discordgo.Session, race *Race, action string, endTime ...time.Time) {
+	log.Trace("--> race.raceMessage")
+	defer log.Trace("<-- race.raceMessage")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	var until time.Duration
+	if len(endTime) > 0 {
+		until = time.Until(endTime[0])
+	} else if action == "join" {
+		until = time.Until(race.StartTime.Add(race.config.WaitToStart))
+	} else {
+		until = time.Until(race.betsClose)
+	}
+
+	var status, description string
+	switch action {
+	case "join":
+		status = "Starts in " + format.Duration(max(until, 0))
+		if race.config.EntryFee > 0 {
+			description = p.Sprintf("A new race is starting. You can join the race for an entry fee of %d credits.", race.config.EntryFee)
+		} else {
+			description = p.Sprintf("A new race is starting. You can join the race for free.")
+		}
+	case "bet":
+		status = "Betting closes in " + format.Duration(max(until, 0))
+		description = p.Sprintf("Place your bets! You can bet %d credits or more on the racer you think will win. Those who bet on the winner share the pool, less a %d%% house cut.", race.config.BetAmount, race.config.HouseCut)
+	case "start":
+		status = "Started"
+		description = p.Sprintf("The race is underway!")
+	case "cancel":
+		status = "Cancelled"
+		description = p.Sprintf("The race was cancelled.")
+	default:
+		status = "Ended"
+		description = p.Sprintf("The race is over.")
+	}
+
+	pool := race.Pool()
+	race.mutex.Lock()
+	racers := make([]string, 0, len(race.Racers))
+	for idx, racer := range race.Racers {
+		if action == "join" {
+			racers = append(racers, fmt.Sprintf("%d. %s %s", idx+1, racer.Racer.Emoji, racer.getName()))
+		} else {
+			racers = append(racers, p.Sprintf("%d. %s %s (%s)", idx+1, racer.Racer.Emoji, racer.getName(), pool.FormatOdds(racer)))
+		}
+	}
+	numBetters := len(race.Betters)
+	race.mutex.Unlock()
+
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Type:        discordgo.EmbedTypeRich,
+			Title:       "Race",
+			Description: description,
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:   "Status",
+					Value:  status,
+					Inline: true,
+				},
+				{
+					Name:   "Bets",
+					Value:  p.Sprintf("%d (%d credit pool)", numBetters, pool.Total),
+					Inline: true,
+				},
+				{
+					Name:   p.Sprintf("Racers (%d)", len(racers)),
+					Value:  strings.Join(racers, "\n"),
+					Inline: false,
+				},
+			},
+		},
+	}
+
+	components := []discordgo.MessageComponent{}
+	switch action {
+	case "join":
+		components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Join",
+				Style:    discordgo.SuccessButton,
+				CustomID: "join_race",
+			},
+		}})
+	case "bet":
+		components = getBetButtons(race)
+	}
+
+	emptymsg := ""
+	_, err := s.InteractionResponseEdit(race.interaction.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+		Content:    &emptymsg,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": race.GuildID, "error": err}).Error("unable to send the race message")
+	}
+}
+
+// getBetButtons returns a button for each racer in the race that members may use to bet on the racer.
+func getBetButtons(race *Race) []discordgo.MessageComponent {
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	rows := make([]discordgo.MessageComponent, 0, 3)
+	buttons := make([]discordgo.MessageComponent, 0, 5)
+	for idx, racer := range race.Racers {
+		if idx >= MAX_BET_BUTTONS {
+			break
+		}
+		button := discordgo.Button{
+			Label:    fmt.Sprintf("%d. %s", idx+1, racer.Member.getName()),
+			Style:    discordgo.PrimaryButton,
+			CustomID: discord.CustomID("race_bet", race.getID(), racer.Member.MemberID),
+		}
+		buttons = append(buttons, button)
+		if len(buttons) == 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: buttons})
+			buttons = make([]discordgo.MessageComponent, 0, 5)
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+
+	return rows
+}
+
+// sendRaceResults sends the results of the race to the channel, and pays out the prizes and bets.
+func sendRaceResults(s *discordgo.Session, race *Race) {
+	log.Trace("--> race.sendRaceResults")
+	defer log.Trace("<-- race.sendRaceResults")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	// Once the winnings start being distributed, the bets should no longer be refunded if the bot is restarted.
+	race.setStage(RACE_COMPLETE)
+	payouts := payoutRace(race)
+	record := newRaceRecord(race, payouts)
+	writeRaceRecord(record)
+
+	result := race.RaceResult
+	fields := make([]*discordgo.MessageEmbedField, 0, 4)
+	places := []struct {
+		name        string
+		participant *RaceParticipant
+		time        float64
+	}{
+		{"Win", result.Win, result.WinTime},
+		{"Place", result.Place, result.PlaceTime},
+		{"Show", result.Show, result.ShowTime},
+	}
+	for _, place := range places {
+		if place.participant == nil {
+			continue
+		}
+		field := &discordgo.MessageEmbedField{
+			Name: p.Sprintf("%s %s", place.name, place.participant.Racer.Emoji),
+			Value: p.Sprintf("%s\nTime: %.2f\nPrize: %d",
+				place.participant.getName(),
+				place.time,
+				payouts.prizes[place.participant.Member.MemberID],
+			),
+			Inline: true,
+		}
+		fields = append(fields, field)
+	}
+
+	var betResults string
+	switch {
+	case len(payouts.betWinners) > 0:
+		betWinners := make([]string, 0, len(payouts.betWinners))
+		for _, better := range payouts.betWinners {
+			betWinners = append(betWinners, p.Sprintf("%s won %d credits", better.Member.getName(), payouts.betWinnings[better]))
+		}
+		betResults = strings.Join(betWinners, "\n")
+	case payouts.pool.Total > 0:
+		betResults = p.Sprintf("No one bet on the winner, so all bets were refunded.")
+	default:
+		betResults = "No one bet on the race."
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:  "Bet Winners",
+		Value: betResults,
+	})
+
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Type:   discordgo.EmbedTypeRich,
+			Title:  "Race Results",
+			Fields: fields,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: p.Sprintf("Race #%d. Use /race replay %d to watch it again.", record.Number, record.Number),
+			},
+		},
+	}
+	_, err := s.ChannelMessageSendComplex(race.interaction.ChannelID, &discordgo.MessageSend{
+		Embeds: embeds,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": race.GuildID, "error": err}).Error("unable to send the race results")
+	}
+
+	raceMessage(s, race, "end")
+}
+
+// racePayouts are the prizes and bet winnings paid out for a race.
+type racePayouts struct {
+	prizes      map[string]int      // Prize paid to each member who finished in the money
+	pool        *BettingPool        // Betting pool for the race
+	betWinners  []*RaceBetter       // Members who bet on the winner of the race
+	betWinnings map[*RaceBetter]int // Amount paid to each member who bet on the winner
+	houseTake   int                 // Amount of the betting pool kept by the house
+}
+
+// payoutRace pays the prizes to the members who won, placed or showed in the race, pays those
+// who bet on the winner, and updates the race statistics for all racers.
+func payoutRace(race *Race) *racePayouts {
+	log.Trace("--> race.payoutRace")
+	defer log.Trace("<-- race.payoutRace")
+
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	payouts := &racePayouts{
+		prizes:     make(map[string]int),
+		pool:       newBettingPool(race.Betters, race.config.HouseCut),
+		betWinners: make([]*RaceBetter, 0, len(race.Betters)),
+	}
+
+	// The prize for the winner includes the entry fees paid by all the racers.
+	prize := race.config.MinPriceAmount
+	if race.config.MaxPrizeAmount > race.config.MinPriceAmount {
+		prize += rand.Intn(race.config.MaxPrizeAmount - race.config.MinPriceAmount + 1)
+	}
+	purse := race.config.EntryFee * len(race.Racers)
+
+	result := race.RaceResult
+	for _, racer := range race.Racers {
+		member := racer.Member
+		switch {
+		case racer == result.Win:
+			payouts.prizes[member.MemberID] = prize + purse
+			member.WinRace(prize + purse)
+		case racer == result.Place:
+			payouts.prizes[member.MemberID] = prize / 2
+			member.PlaceInRace(prize / 2)
+		case racer == result.Show:
+			payouts.prizes[member.MemberID] = prize / 4
+			member.ShowInRace(prize / 4)
+		default:
+			member.LoseRace()
+		}
+		if racer.Owned != nil {
+			racer.Owned.finishRace(racer == result.Win)
+		}
+	}
+
+	// Those who bet on the winner share the betting pool. If no one did, the bets are refunded.
+	betPayouts, houseTake := payouts.pool.Payouts(race.Betters, result.Win)
+	payouts.betWinnings = make(map[*RaceBetter]int, len(betPayouts))
+	payouts.houseTake = houseTake
+	for _, better := range race.Betters {
+		amount := betPayouts[better]
+		switch {
+		case better.Racer == result.Win:
+			better.Member.WinBet(amount)
+			payouts.betWinners = append(payouts.betWinners, better)
+			payouts.betWinnings[better] = amount
+		case amount > 0:
+			better.Member.RefundBet(amount)
+		}
+	}
+	log.WithFields(log.Fields{"guild": race.GuildID, "prize": prize, "purse": purse, "pool": payouts.pool.Total, "houseTake": houseTake, "betWinners": len(payouts.betWinners)}).Info("race payouts")
+
+	return payouts
 }
@@ -11,7 +11,11 @@ import (
 type Config struct {
 	ID               primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	GuildID          string             `json:"guild_id" bson:"guild_id"`
-	BetAmount        int64              `json:"bet_amount" bson:"bet_amount"`
+	BetAmount        int64              `json:"bet_amount" bson:"bet_amount"` // Minimum amount that may be bet on a racer
+	HouseCut         int                `json:"house_cut" bson:"house_cut"`   // Percent of the betting pool kept by the house
+	RacerCost        int                `json:"racer_cost" bson:"racer_cost"`
+	TrainingCost     int                `json:"training_cost" bson:"training_cost"`
+	EntryFee         int                `json:"entry_fee" bson:"entry_fee"`
 	Currency         string             `json:"currency" bson:"currency"`
 	LastRaceEnded    time.Time          `json:"last_race_ended" bson:"last_race_ended"`
 	MaxNumRacers     int                `json:"max_num_racers" bson:"max_num_racers"`
@@ -33,6 +37,7 @@ func GetConfig(guildID string) *Config {
 	if err != nil {
 		config = newConfig(guildID)
 	}
+	config.setDefaults()
 	return config
 }
 
@@ -59,6 +64,10 @@ func newConfig(guildID string) *Config {
 		GuildID:          guildID,
 		Theme:            "clash",
 		BetAmount:        100,
+		HouseCut:         HOUSE_CUT,
+		RacerCost:        RACER_COST,
+		TrainingCost:     TRAINING_COST,
+		EntryFee:         0,
 		Currency:         "credit",
 		LastRaceEnded:    time.Time{},
 		StartingLine:     "🏁",
@@ -76,3 +85,17 @@ func newConfig(guildID string) *Config {
 
 	return config
 }
+
+// setDefaults sets the default values for any configuration fields that were added after the
+// configuration was saved to the database.
+func (c *Config) setDefaults() {
+	if c.HouseCut == 0 {
+		c.HouseCut = HOUSE_CUT
+	}
+	if c.RacerCost == 0 {
+		c.RacerCost = RACER_COST
+	}
+	if c.TrainingCost == 0 {
+		c.TrainingCost = TRAINING_COST
+	}
+}
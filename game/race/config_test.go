@@ -40,3 +40,21 @@ func TestGetConfig(t *testing.T) {
 		return
 	}
 }
+
+func TestSetDefaults(t *testing.T) {
+	// A configuration saved before the costs and house cut were added
+	config := &Config{GuildID: "123"}
+	config.setDefaults()
+	if config.HouseCut != HOUSE_CUT {
+		t.Errorf("expected a house cut of %d, got %d", HOUSE_CUT, config.HouseCut)
+	}
+	if config.RacerCost != RACER_COST || config.TrainingCost != TRAINING_COST {
+		t.Errorf("expected costs of %d and %d, got %d and %d", RACER_COST, TRAINING_COST, config.RacerCost, config.TrainingCost)
+	}
+
+	config.HouseCut = 10
+	config.setDefaults()
+	if config.HouseCut != 10 {
+		t.Errorf("expected the house cut to be unchanged, got %d", config.HouseCut)
+	}
+}
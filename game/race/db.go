@@ -7,9 +7,15 @@ import (
 )
 
 const (
-	RACE_CONFIG_COLLECTION = "race_configs"
-	RACE_MEMBER_COLLECTION = "race_members"
-	RACER_COLLECTION       = "race_racers"
+	RACE_CONFIG_COLLECTION  = "race_configs"
+	RACE_MEMBER_COLLECTION  = "race_members"
+	RACER_COLLECTION        = "race_racers"
+	RACE_THEME_COLLECTION   = "race_themes"
+	OWNED_RACER_COLLECTION  = "race_owned_racers"
+	TOURNAMENT_COLLECTION   = "race_tournaments"
+	RACE_RECORD_COLLECTION  = "race_records"
+	RACE_STATE_COLLECTION   = "race_states"
+	RACE_PROFILE_COLLECTION = "race_movement_profiles"
 )
 
 // readConfig loads the race configuration from the database. If it does not exist then
@@ -116,3 +122,327 @@ func writeRacer(racer *Racer) {
 	db.UpdateOrInsert(RACER_COLLECTION, filter, racer)
 	log.WithFields(log.Fields{"guild": racer.GuildID, "target": racer.Theme}).Debug("create or update target")
 }
+
+// deleteRacer removes the racer with the given emoji from a theme in the database. An error is
+// returned if the racer doesn't exist.
+func deleteRacer(guildID string, themeName string, emoji string) error {
+	log.Trace("--> race.deleteRacer")
+	defer log.Trace("<-- race.deleteRacer")
+
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "theme", Value: themeName}, {Key: "emoji", Value: emoji}}
+	count, err := db.Count(RACER_COLLECTION, filter)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrRacerNotFound
+	}
+	err = db.Delete(RACER_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "theme": themeName, "emoji": emoji, "error": err}).Error("unable to delete the racer from the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "theme": themeName, "emoji": emoji}).Debug("delete racer from the database")
+
+	return nil
+}
+
+// readThemes loads the race themes created for a guild.
+func readThemes(guildID string) ([]*Theme, error) {
+	log.Trace("--> race.readThemes")
+	defer log.Trace("<-- race.readThemes")
+
+	var themes []*Theme
+	filter := bson.D{{Key: "guild_id", Value: guildID}}
+	sort := bson.D{{Key: "name", Value: 1}}
+	err := db.FindMany(RACE_THEME_COLLECTION, filter, &themes, sort, 0)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Warn("unable to read race themes")
+		return nil, err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "count": len(themes)}).Debug("read race themes")
+
+	return themes, nil
+}
+
+// writeTheme creates or updates a race theme for a guild in the database.
+func writeTheme(theme *Theme) {
+	log.Trace("--> race.writeTheme")
+	defer log.Trace("<-- race.writeTheme")
+
+	filter := bson.D{{Key: "guild_id", Value: theme.GuildID}, {Key: "name", Value: theme.Name}}
+	err := db.UpdateOrInsert(RACE_THEME_COLLECTION, filter, theme)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": theme.GuildID, "theme": theme.Name, "error": err}).Error("unable to write the race theme to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": theme.GuildID, "theme": theme.Name}).Debug("write race theme to the database")
+}
+
+// readRaceStates loads the saved state for all races that had not ended when the bot was stopped.
+func readRaceStates() ([]*RaceState, error) {
+	log.Trace("--> race.readRaceStates")
+	defer log.Trace("<-- race.readRaceStates")
+
+	var states []*RaceState
+	err := db.FindMany(RACE_STATE_COLLECTION, bson.D{}, &states, bson.D{}, 0)
+	if err != nil {
+		log.WithField("error", err).Error("unable to read race states")
+		return nil, err
+	}
+
+	log.WithField("states", len(states)).Debug("read race states")
+
+	return states, nil
+}
+
+// writeRaceState creates or updates the saved state of the race for a guild in the database.
+func writeRaceState(state *RaceState) {
+	log.Trace("--> race.writeRaceState")
+	defer log.Trace("<-- race.writeRaceState")
+
+	filter := bson.M{"guild_id": state.GuildID}
+	err := db.UpdateOrInsert(RACE_STATE_COLLECTION, filter, state)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": state.GuildID, "error": err}).Error("unable to save the race state to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": state.GuildID, "stage": state.Stage}).Debug("write race state to the database")
+}
+
+// deleteRaceState removes the saved state of the race for a guild from the database.
+func deleteRaceState(guildID string) {
+	log.Trace("--> race.deleteRaceState")
+	defer log.Trace("<-- race.deleteRaceState")
+
+	filter := bson.M{"guild_id": guildID}
+	err := db.Delete(RACE_STATE_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to delete the race state from the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": guildID}).Debug("delete race state from the database")
+}
+
+// readMovementProfiles loads the movement profiles defined for a guild and theme.
+func readMovementProfiles(guildID string, theme string) ([]*MovementProfile, error) {
+	log.Trace("--> race.readMovementProfiles")
+	defer log.Trace("<-- race.readMovementProfiles")
+
+	var profiles []*MovementProfile
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "theme", Value: theme}}
+	sort := bson.D{{Key: "name", Value: 1}}
+	err := db.FindMany(RACE_PROFILE_COLLECTION, filter, &profiles, sort, 0)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "theme": theme, "error": err}).Warn("unable to read movement profiles")
+		return nil, err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "theme": theme, "count": len(profiles)}).Debug("read movement profiles")
+
+	return profiles, nil
+}
+
+// writeMovementProfile creates or updates a movement profile for a guild and theme in the database.
+func writeMovementProfile(profile *MovementProfile) {
+	log.Trace("--> race.writeMovementProfile")
+	defer log.Trace("<-- race.writeMovementProfile")
+
+	filter := bson.D{{Key: "guild_id", Value: profile.GuildID}, {Key: "theme", Value: profile.Theme}, {Key: "name", Value: profile.Name}}
+	err := db.UpdateOrInsert(RACE_PROFILE_COLLECTION, filter, profile)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": profile.GuildID, "theme": profile.Theme, "profile": profile.Name, "error": err}).Error("unable to write the movement profile to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": profile.GuildID, "theme": profile.Theme, "profile": profile.Name}).Debug("write movement profile to the database")
+}
+
+// deleteMovementProfile removes a movement profile for a guild and theme from the database. An
+// error is returned if the profile doesn't exist.
+func deleteMovementProfile(guildID string, theme string, name string) error {
+	log.Trace("--> race.deleteMovementProfile")
+	defer log.Trace("<-- race.deleteMovementProfile")
+
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "theme", Value: theme}, {Key: "name", Value: name}}
+	count, err := db.Count(RACE_PROFILE_COLLECTION, filter)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrProfileNotFound
+	}
+	err = db.Delete(RACE_PROFILE_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "theme": theme, "profile": name, "error": err}).Error("unable to delete the movement profile from the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "theme": theme, "profile": name}).Debug("delete movement profile from the database")
+
+	return nil
+}
+
+// readOwnedRacer loads the racer owned by the member. If the member doesn't own a racer, then
+// `nil` is returned.
+func readOwnedRacer(guildID string, memberID string) *OwnedRacer {
+	log.Trace("--> race.readOwnedRacer")
+	defer log.Trace("<-- race.readOwnedRacer")
+
+	var owned OwnedRacer
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "member_id", Value: memberID}}
+	err := db.FindOne(OWNED_RACER_COLLECTION, filter, &owned)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "member": memberID}).Debug("owned racer not found in the database")
+		return nil
+	}
+	log.WithFields(log.Fields{"guild": guildID, "member": memberID}).Debug("read owned racer from the database")
+
+	return &owned
+}
+
+// writeOwnedRacer creates or updates the racer owned by a member in the database.
+func writeOwnedRacer(owned *OwnedRacer) {
+	log.Trace("--> race.writeOwnedRacer")
+	defer log.Trace("<-- race.writeOwnedRacer")
+
+	filter := bson.D{{Key: "guild_id", Value: owned.GuildID}, {Key: "member_id", Value: owned.MemberID}}
+	err := db.UpdateOrInsert(OWNED_RACER_COLLECTION, filter, owned)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": owned.GuildID, "member": owned.MemberID, "error": err}).Error("unable to write the owned racer to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": owned.GuildID, "member": owned.MemberID}).Debug("write owned racer to the database")
+}
+
+// readActiveTournament loads the tournament that is open for registration or running in the
+// guild. If there isn't one, then `nil` is returned.
+func readActiveTournament(guildID string) *Tournament {
+	log.Trace("--> race.readActiveTournament")
+	defer log.Trace("<-- race.readActiveTournament")
+
+	var tournament Tournament
+	filter := bson.D{
+		{Key: "guild_id", Value: guildID},
+		{Key: "stage", Value: bson.D{{Key: "$in", Value: bson.A{TOURNAMENT_REGISTRATION, TOURNAMENT_RUNNING}}}},
+	}
+	err := db.FindOne(TOURNAMENT_COLLECTION, filter, &tournament)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID}).Debug("active tournament not found in the database")
+		return nil
+	}
+	log.WithFields(log.Fields{"guild": guildID, "tournament": tournament.Name}).Debug("read active tournament from the database")
+
+	return &tournament
+}
+
+// readRunningTournaments loads the tournaments in all guilds that are running.
+func readRunningTournaments() ([]*Tournament, error) {
+	log.Trace("--> race.readRunningTournaments")
+	defer log.Trace("<-- race.readRunningTournaments")
+
+	var tournaments []*Tournament
+	filter := bson.D{{Key: "stage", Value: TOURNAMENT_RUNNING}}
+	err := db.FindMany(TOURNAMENT_COLLECTION, filter, &tournaments, bson.D{}, 0)
+	if err != nil {
+		log.WithField("error", err).Error("unable to read running tournaments")
+		return nil, err
+	}
+	log.WithField("count", len(tournaments)).Debug("read running tournaments")
+
+	return tournaments, nil
+}
+
+// countTournaments returns the number of tournaments that have been held in the guild.
+func countTournaments(guildID string) int {
+	log.Trace("--> race.countTournaments")
+	defer log.Trace("<-- race.countTournaments")
+
+	filter := bson.D{{Key: "guild_id", Value: guildID}}
+	count, err := db.Count(TOURNAMENT_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to count tournaments")
+		return 0
+	}
+	return count
+}
+
+// writeTournament creates or updates a tournament in the database.
+func writeTournament(tournament *Tournament) {
+	log.Trace("--> race.writeTournament")
+	defer log.Trace("<-- race.writeTournament")
+
+	if tournament.ID == primitive.NilObjectID {
+		tournament.ID = primitive.NewObjectID()
+	}
+	filter := bson.D{{Key: "_id", Value: tournament.ID}}
+	err := db.UpdateOrInsert(TOURNAMENT_COLLECTION, filter, tournament)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": tournament.GuildID, "tournament": tournament.Name, "error": err}).Error("unable to write the tournament to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": tournament.GuildID, "tournament": tournament.Name, "stage": tournament.Stage}).Debug("write tournament to the database")
+}
+
+// readRaceRecord loads the record of the race with the given number in the guild.
+func readRaceRecord(guildID string, number int) (*RaceRecord, error) {
+	log.Trace("--> race.readRaceRecord")
+	defer log.Trace("<-- race.readRaceRecord")
+
+	var record RaceRecord
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "number", Value: number}}
+	err := db.FindOne(RACE_RECORD_COLLECTION, filter, &record)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "number": number}).Debug("race record not found in the database")
+		return nil, ErrRaceRecordNotFound
+	}
+	log.WithFields(log.Fields{"guild": guildID, "number": number}).Debug("read race record from the database")
+
+	return &record, nil
+}
+
+// readRecentRaceRecords loads the most recent race records in the guild, newest first.
+func readRecentRaceRecords(guildID string, limit int64) ([]*RaceRecord, error) {
+	log.Trace("--> race.readRecentRaceRecords")
+	defer log.Trace("<-- race.readRecentRaceRecords")
+
+	var records []*RaceRecord
+	filter := bson.D{{Key: "guild_id", Value: guildID}}
+	sort := bson.D{{Key: "number", Value: -1}}
+	err := db.FindMany(RACE_RECORD_COLLECTION, filter, &records, sort, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to read race records")
+		return nil, err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "count": len(records)}).Debug("read race records from the database")
+
+	return records, nil
+}
+
+// countRaceRecords returns the number of races recorded in the guild.
+func countRaceRecords(guildID string) int {
+	log.Trace("--> race.countRaceRecords")
+	defer log.Trace("<-- race.countRaceRecords")
+
+	filter := bson.D{{Key: "guild_id", Value: guildID}}
+	count, err := db.Count(RACE_RECORD_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to count race records")
+		return 0
+	}
+	return count
+}
+
+// writeRaceRecord saves the record of a race to the database.
+func writeRaceRecord(record *RaceRecord) {
+	log.Trace("--> race.writeRaceRecord")
+	defer log.Trace("<-- race.writeRaceRecord")
+
+	if record.ID == primitive.NilObjectID {
+		record.ID = primitive.NewObjectID()
+	}
+	filter := bson.D{{Key: "_id", Value: record.ID}}
+	err := db.UpdateOrInsert(RACE_RECORD_COLLECTION, filter, record)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": record.GuildID, "error": err}).Error("unable to save the race record to the database")
+		return
+	}
+	log.WithFields(log.Fields{"guild": record.GuildID, "number": record.Number}).Debug("write race record to the database")
+}
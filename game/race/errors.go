@@ -1,10 +1,114 @@
 package race
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/rbrabson/goblin/internal/discmsg"
+	"github.com/rbrabson/goblin/internal/format"
+	"golang.org/x/text/language"
+)
 
 var (
-	ErrConfigNotFound = errors.New("configuration file not found")
-	ErrMemberNotFound = errors.New("member not found")
-	ErrRacerNotFound  = errors.New("racer not found")
-	ErrNoRacersFound  = errors.New("no racers found")
+	ErrAlreadyBet           = errors.New("you have already placed a bet on the race")
+	ErrAlreadyOwnRacer      = errors.New("you already own a racer")
+	ErrAlreadyRegistered    = errors.New("you have already registered for the tournament")
+	ErrFullyTrained         = errors.New("your racer is fully trained")
+	ErrInvalidRacerName     = errors.New("the racer's name must be between 1 and 32 characters")
+	ErrNoOwnedRacer         = errors.New("you don't own a racer")
+	ErrAlreadyJoinedRace    = errors.New("you have already joined the race")
+	ErrBettingClosed        = errors.New("betting is not open for the race")
+	ErrConfigNotFound       = errors.New("configuration file not found")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrNoRace               = errors.New("no race is being planned")
+	ErrNoTournament         = errors.New("there is no tournament open or running")
+	ErrNotEnoughEntrants    = errors.New("not enough members have registered for the tournament")
+	ErrRaceAlreadyStarted   = errors.New("the race has already started")
+	ErrRaceFull             = errors.New("the race is full")
+	ErrRaceInProgress       = errors.New("a race is already in progress")
+	ErrRacerNotFound        = errors.New("racer not found")
+	ErrRaceRecordNotFound   = errors.New("no race with that number was found")
+	ErrRegistrationClosed   = errors.New("registration for the tournament is closed")
+	ErrNoRacersFound        = errors.New("no racers found")
+	ErrProfileNotFound      = errors.New("movement profile not found")
+	ErrRacerExists          = errors.New("the racer is already part of the theme")
+	ErrThemeExists          = errors.New("the theme already exists")
+	ErrThemeNotFound        = errors.New("theme not found")
+	ErrTournamentFull       = errors.New("the tournament is full")
+	ErrTournamentInProgress = errors.New("a tournament is already open or running")
 )
+
+// ErrInvalidProfile is returned when a movement profile can't be used for racing.
+type ErrInvalidProfile struct {
+	Reason string
+}
+
+// Error returns the error message for ErrInvalidProfile.
+func (e ErrInvalidProfile) Error() string {
+	return "invalid movement profile: " + e.Reason
+}
+
+// ErrInvalidTournament is returned when a tournament can't be opened with the given settings.
+type ErrInvalidTournament struct {
+	Reason string
+}
+
+// Error returns the error message for ErrInvalidTournament.
+func (e ErrInvalidTournament) Error() string {
+	return "invalid tournament: " + e.Reason
+}
+
+// ErrRaceTooSoon is returned when a race is started before enough time has passed since the last one.
+type ErrRaceTooSoon struct {
+	RemainingTime time.Duration
+}
+
+// Error returns the error message for ErrRaceTooSoon.
+func (e ErrRaceTooSoon) Error() string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	return p.Sprintf("The racers are still resting after the last race. The next race may start in %s.", format.Duration(e.RemainingTime))
+}
+
+// ErrInvalidBet is returned when the amount bet on a racer isn't a number or is less than the minimum bet.
+type ErrInvalidBet struct {
+	MinimumBet int
+}
+
+// Error returns the error message for ErrInvalidBet.
+func (e ErrInvalidBet) Error() string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	return p.Sprintf("You must bet a whole number of at least %d credits.", e.MinimumBet)
+}
+
+// ErrNeedsExperience is returned when an owned racer needs more experience before it can be trained.
+type ErrNeedsExperience struct {
+	Experience int
+}
+
+// Error returns the error message for ErrNeedsExperience.
+func (e ErrNeedsExperience) Error() string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	return p.Sprintf("Your racer needs %d more experience before it can be trained again.", e.Experience)
+}
+
+// ErrRacerTired is returned when an owned racer needs to rest before it can race again.
+type ErrRacerTired struct {
+	RemainingTime time.Duration
+}
+
+// Error returns the error message for ErrRacerTired.
+func (e ErrRacerTired) Error() string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	return p.Sprintf("Your racer is still resting after its last race. It may race again in %s.", format.Duration(e.RemainingTime))
+}
+
+// ErrNotEnoughCredits is returned when a member does not have enough credits to join or bet on a race.
+type ErrNotEnoughCredits struct {
+	CreditsNeeded int
+}
+
+// Error returns the error message for ErrNotEnoughCredits.
+func (e ErrNotEnoughCredits) Error() string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	return p.Sprintf("You do not have enough credits. You need %d credits to participate.", e.CreditsNeeded)
+}
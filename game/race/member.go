@@ -2,6 +2,7 @@ package race
 
 import (
 	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -20,6 +21,7 @@ type RaceMember struct {
 	BetsMade      int                `json:"bets_made" bson:"bets_made"`
 	BetsWon       int                `json:"bets_won" bson:"bets_won"`
 	TotalEarnings int                `json:"total_earnings" bson:"total_earnings"`
+	guildMember   *guild.Member      `json:"-" bson:"-"`
 }
 
 // GetRaceMember gets a race member. THe member is created if it doesn't exist.
@@ -64,15 +66,24 @@ func newRaceMember(guildID string, memberID string) *RaceMember {
 	return member
 }
 
+// getName returns the name of the member on the guild.
+func (m *RaceMember) getName() string {
+	if m.guildMember == nil {
+		m.guildMember = guild.GetMember(m.GuildID, m.MemberID)
+	}
+	return m.guildMember.Name
+}
+
 // WinRace is called when the race member won a race.
 func (m *RaceMember) WinRace(amount int) {
 	log.Trace("--> race.Member.WinRace")
 	log.Trace("<-- race.Member.WinRace")
 
 	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
-	bankAccount.Deposit(amount)
+	bankAccount.DepositWithReason(amount, "race prize")
 
 	m.RacesWon++
+	m.TotalRaces++
 	m.TotalEarnings += amount
 	writeRaceMember(m)
 
@@ -85,9 +96,10 @@ func (m *RaceMember) PlaceInRace(amount int) {
 	log.Trace("<-- race.Member.PlaceInRace")
 
 	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
-	bankAccount.Deposit(amount)
+	bankAccount.DepositWithReason(amount, "race prize")
 
 	m.RacesPlaced++
+	m.TotalRaces++
 	m.TotalEarnings += amount
 	writeRaceMember(m)
 
@@ -101,9 +113,10 @@ func (m *RaceMember) ShowInRace(amount int) {
 	log.Trace("<-- race.Member.ShowInRace")
 
 	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
-	bankAccount.Deposit(amount)
+	bankAccount.DepositWithReason(amount, "race prize")
 
 	m.RacesShowed++
+	m.TotalRaces++
 	m.TotalEarnings += amount
 	writeRaceMember(m)
 
@@ -116,6 +129,7 @@ func (m *RaceMember) LoseRace() {
 	log.Trace("<-- race.Member.LoseRace")
 
 	m.RacesLost++
+	m.TotalRaces++
 	writeRaceMember(m)
 
 	log.WithFields(log.Fields{"guild": m.GuildID, "member": m.MemberID}).Info("lost race")
@@ -127,7 +141,7 @@ func (m *RaceMember) PlaceBet(betAmount int) error {
 	defer log.Trace("<-- race.Member.PlaceBet")
 
 	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
-	err := bankAccount.Withdraw(betAmount)
+	err := bankAccount.WithdrawWithReason(betAmount, "race bet")
 	if err != nil {
 		return err
 	}
@@ -147,7 +161,7 @@ func (m *RaceMember) WinBet(winnings int) {
 	defer log.Trace("<-- race.Member.WinBet")
 
 	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
-	bankAccount.Deposit(winnings)
+	bankAccount.DepositWithReason(winnings, "race bet winnings")
 
 	m.BetsWon++
 	m.BetsEarnings += winnings
@@ -156,3 +170,17 @@ func (m *RaceMember) WinBet(winnings int) {
 
 	log.WithFields(log.Fields{"guild": m.GuildID, "member": m.MemberID, "winnings": winnings}).Info("won bet")
 }
+
+// RefundBet is used to return a bet to a member when no one bet on the winner of a race.
+func (m *RaceMember) RefundBet(amount int) {
+	log.Trace("--> race.Member.RefundBet")
+	defer log.Trace("<-- race.Member.RefundBet")
+
+	bankAccount := bank.GetAccount(m.GuildID, m.MemberID)
+	bankAccount.DepositWithReason(amount, "race bet refund")
+
+	m.TotalEarnings += amount
+	writeRaceMember(m)
+
+	log.WithFields(log.Fields{"guild": m.GuildID, "member": m.MemberID, "amount": amount}).Info("refunded bet")
+}
@@ -0,0 +1,201 @@
+package race
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/rbrabson/goblin/bank"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RACER_COST         = 5000 // Default cost to buy a racer
+	TRAINING_COST      = 1000 // Default cost of the first level of training, which increases with each level
+	MAX_TRAINING       = 5    // Maximum number of times a racer may be trained
+	XP_PER_RACE        = 10   // Experience gained for running in a race
+	XP_PER_WIN         = 25   // Additional experience gained for winning a race
+	XP_PER_LEVEL       = 100  // Experience needed to gain a level
+	MAX_FATIGUE        = 100
+	FATIGUE_PER_RACE   = 60 // Fatigue gained for running in a race
+	FATIGUE_RECOVERY   = 30 // Fatigue lost per hour of rest
+	MAX_OWNED_NAME_LEN = 32
+)
+
+// OwnedRacer is a racer bought by a member. The member races with their own racer rather than
+// one assigned at random. The racer gains experience from each race, which lets it be trained
+// to improve its movement, and gets tired, which means it needs to rest between races.
+type OwnedRacer struct {
+	ID             primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID        string             `json:"guild_id" bson:"guild_id"`
+	MemberID       string             `json:"member_id" bson:"member_id"`
+	Name           string             `json:"name" bson:"name"`
+	Emoji          string             `json:"emoji" bson:"emoji"`
+	MovementSpeed  string             `json:"movement_speed" bson:"movement_speed"`
+	Experience     int                `json:"experience" bson:"experience"`
+	Training       int                `json:"training" bson:"training"`
+	Fatigue        float64            `json:"fatigue" bson:"fatigue"`
+	FatigueUpdated time.Time          `json:"fatigue_updated" bson:"fatigue_updated"`
+	Races          int                `json:"races" bson:"races"`
+	Wins           int                `json:"wins" bson:"wins"`
+	Purchased      time.Time          `json:"purchased" bson:"purchased"`
+}
+
+// GetOwnedRacer returns the racer owned by the member, or `nil` if the member doesn't own one.
+func GetOwnedRacer(guildID string, memberID string) *OwnedRacer {
+	log.Trace("--> race.GetOwnedRacer")
+	defer log.Trace("<-- race.GetOwnedRacer")
+
+	return readOwnedRacer(guildID, memberID)
+}
+
+// BuyRacer buys a racer for the member. The racer is one of the racers for the current theme,
+// chosen by emoji, or at random if no emoji is given.
+func BuyRacer(guildID string, memberID string, name string, emoji string) (*OwnedRacer, error) {
+	log.Trace("--> race.BuyRacer")
+	defer log.Trace("<-- race.BuyRacer")
+
+	if GetOwnedRacer(guildID, memberID) != nil {
+		return nil, ErrAlreadyOwnRacer
+	}
+	if name == "" || len(name) > MAX_OWNED_NAME_LEN {
+		return nil, ErrInvalidRacerName
+	}
+
+	config := GetConfig(guildID)
+	racers := GetRacers(guildID, config.Theme)
+	if len(racers) == 0 {
+		return nil, ErrNoRacersFound
+	}
+	var racer *Racer
+	if emoji == "" {
+		racer = racers[rand.Intn(len(racers))]
+	} else {
+		for _, r := range racers {
+			if r.Emoji == emoji {
+				racer = r
+				break
+			}
+		}
+		if racer == nil {
+			return nil, ErrRacerNotFound
+		}
+	}
+
+	account := bank.GetAccount(guildID, memberID)
+	if err := account.WithdrawWithReason(config.RacerCost, "bought a racer"); err != nil {
+		return nil, ErrNotEnoughCredits{CreditsNeeded: config.RacerCost}
+	}
+
+	owned := &OwnedRacer{
+		GuildID:       guildID,
+		MemberID:      memberID,
+		Name:          name,
+		Emoji:         racer.Emoji,
+		MovementSpeed: racer.MovementSpeed,
+		Purchased:     time.Now(),
+	}
+	writeOwnedRacer(owned)
+	log.WithFields(log.Fields{"guild": guildID, "member": memberID, "name": name, "emoji": owned.Emoji}).Info("bought racer")
+
+	return owned, nil
+}
+
+// Train spends the member's credits to improve the racer's movement. A racer may not be
+// trained beyond its level, which is gained through experience.
+func (o *OwnedRacer) Train(config *Config) error {
+	log.Trace("--> race.OwnedRacer.Train")
+	defer log.Trace("<-- race.OwnedRacer.Train")
+
+	if o.Training >= MAX_TRAINING {
+		return ErrFullyTrained
+	}
+	if o.Training >= o.Level() {
+		return ErrNeedsExperience{Experience: o.Training*XP_PER_LEVEL - o.Experience}
+	}
+
+	cost := o.TrainingCost(config)
+	account := bank.GetAccount(o.GuildID, o.MemberID)
+	if err := account.WithdrawWithReason(cost, "trained a racer"); err != nil {
+		return ErrNotEnoughCredits{CreditsNeeded: cost}
+	}
+
+	o.Training++
+	writeOwnedRacer(o)
+	log.WithFields(log.Fields{"guild": o.GuildID, "member": o.MemberID, "training": o.Training}).Info("trained racer")
+
+	return nil
+}
+
+// TrainingCost returns the cost of the next level of training for the racer.
+func (o *OwnedRacer) TrainingCost(config *Config) int {
+	return config.TrainingCost * (o.Training + 1)
+}
+
+// Level returns the level the racer has reached through experience.
+func (o *OwnedRacer) Level() int {
+	return 1 + o.Experience/XP_PER_LEVEL
+}
+
+// GetFatigue returns how tired the racer is, after accounting for the rest it has had since
+// its last race.
+func (o *OwnedRacer) GetFatigue() float64 {
+	return o.fatigueAt(time.Now())
+}
+
+// fatigueAt returns how tired the racer is at the given time.
+func (o *OwnedRacer) fatigueAt(now time.Time) float64 {
+	if o.Fatigue <= 0 {
+		return 0
+	}
+	hours := max(now.Sub(o.FatigueUpdated).Hours(), 0)
+	return math.Max(o.Fatigue-hours*FATIGUE_RECOVERY, 0)
+}
+
+// RestRemaining returns how long the racer must rest before it may race again. If the racer
+// may race now, then zero is returned.
+func (o *OwnedRacer) RestRemaining() time.Duration {
+	excess := o.GetFatigue() + FATIGUE_PER_RACE - MAX_FATIGUE
+	if excess <= 0 {
+		return 0
+	}
+	return time.Duration(excess / FATIGUE_RECOVERY * float64(time.Hour)).Round(time.Second)
+}
+
+// getRacer returns the racer used when the owned racer enters a race. Training shifts the
+// racer's movement profile, raising its minimum roll by one for each level of training and
+// its maximum roll by one for every two levels.
+func (o *OwnedRacer) getRacer(profiles map[string]*MovementProfile) *Racer {
+	base, ok := profiles[o.MovementSpeed]
+	if !ok {
+		base = getBuiltinProfile(o.MovementSpeed)
+	}
+	profile := *base
+	profile.MaxRoll += o.Training / 2
+	profile.MinRoll = min(profile.MinRoll+o.Training, profile.MaxRoll)
+
+	return &Racer{
+		GuildID:       o.GuildID,
+		Emoji:         o.Emoji,
+		MovementSpeed: o.MovementSpeed,
+		profile:       &profile,
+	}
+}
+
+// finishRace updates the racer after it has run in a race.
+func (o *OwnedRacer) finishRace(won bool) {
+	log.Trace("--> race.OwnedRacer.finishRace")
+	defer log.Trace("<-- race.OwnedRacer.finishRace")
+
+	now := time.Now()
+	o.Races++
+	o.Experience += XP_PER_RACE
+	if won {
+		o.Wins++
+		o.Experience += XP_PER_WIN
+	}
+	o.Fatigue = math.Min(o.fatigueAt(now)+FATIGUE_PER_RACE, MAX_FATIGUE)
+	o.FatigueUpdated = now
+	writeOwnedRacer(o)
+}
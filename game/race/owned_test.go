@@ -0,0 +1,49 @@
+package race
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOwnedRacerFatigue(t *testing.T) {
+	now := time.Now()
+	owned := &OwnedRacer{Fatigue: MAX_FATIGUE, FatigueUpdated: now}
+	if owned.RestRemaining() <= 0 {
+		t.Error("expected a tired racer to need rest")
+	}
+
+	fatigue := owned.fatigueAt(now.Add(time.Hour))
+	if fatigue != MAX_FATIGUE-FATIGUE_RECOVERY {
+		t.Errorf("expected fatigue of %d after an hour, got %.0f", MAX_FATIGUE-FATIGUE_RECOVERY, fatigue)
+	}
+
+	owned.FatigueUpdated = now.Add(-24 * time.Hour)
+	if owned.GetFatigue() != 0 {
+		t.Errorf("expected a rested racer to have no fatigue, got %.0f", owned.GetFatigue())
+	}
+	if owned.RestRemaining() != 0 {
+		t.Error("expected a rested racer to be able to race")
+	}
+}
+
+func TestOwnedRacerTraining(t *testing.T) {
+	profiles := map[string]*MovementProfile{
+		"fast": {Name: "fast", MinRoll: 0, MaxRoll: 4, Multiplier: 3},
+	}
+	owned := &OwnedRacer{MovementSpeed: "fast", Training: 3}
+	racer := owned.getRacer(profiles)
+	if racer.profile.MinRoll != 3 || racer.profile.MaxRoll != 5 {
+		t.Errorf("expected a trained roll of 3-5, got %d-%d", racer.profile.MinRoll, racer.profile.MaxRoll)
+	}
+	if profiles["fast"].MinRoll != 0 {
+		t.Error("expected the base profile to be unchanged")
+	}
+
+	if owned.Level() != 1 {
+		t.Errorf("expected level 1, got %d", owned.Level())
+	}
+	owned.Experience = 2 * XP_PER_LEVEL
+	if owned.Level() != 3 {
+		t.Errorf("expected level 3, got %d", owned.Level())
+	}
+}
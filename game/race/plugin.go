@@ -2,11 +2,12 @@ package race
 
 import (
 	"fmt"
-	"slices"
+	"maps"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
 	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -32,31 +33,37 @@ func Start() {
 // Initialize saves the Discord bot to be used by the banking system
 func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 	db = d
+	b.Session.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
+		recoverRaces(s)
+		resumeTournaments(s)
+	})
 }
 
 // GetCommands returns the commands for the banking system
 func (plugin *Plugin) GetCommands() []*discordgo.ApplicationCommand {
-	commands := make([]*discordgo.ApplicationCommand, 0, len(adminCommands)+len(memberCommands))
-	commands = append(commands, adminCommands...)
-	commands = append(commands, memberCommands...)
+	commands := make([]*discordgo.ApplicationCommand, 0, 2)
+	commands = append(commands, adminRouter.Commands()...)
+	commands = append(commands, memberRouter.Commands()...)
 	return commands
 }
 
+// GetCommandPermissions returns the permission required to use each of the commands for the race
+func (plugin *Plugin) GetCommandPermissions() map[string]guild.Permission {
+	permissions := memberRouter.Permissions(guild.PermissionMember)
+	maps.Copy(permissions, adminRouter.Permissions(guild.PermissionAdmin))
+	return permissions
+}
+
 // GetCommandHandlers returns the command handlers for the banking system
 func (plugin *Plugin) GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	handlers := memberRouter.Handlers()
+	maps.Copy(handlers, adminRouter.Handlers())
+	return handlers
 }
 
 // GetComponentHandlers returns the component handlers for the banking system
 func (plugin *Plugin) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	handlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate))
-	for k, v := range commandHandlers {
-		handlers[k] = v
-	}
-	for k, v := range componentHandlers {
-		handlers[k] = v
-	}
-	return handlers
+	return componentHandlers
 }
 
 // GetName returns the name of the banking system plugin
@@ -66,31 +73,12 @@ func (plugin *Plugin) GetName() string {
 
 // GetHelp returns the member help for the banking system
 func (plugin *Plugin) GetHelp() []string {
-	help := make([]string, 0, 1)
-
-	for _, command := range memberCommands[0].Options {
-		commandDescription := fmt.Sprintf("- **/%s %s**:  %s\n", PLUGIN_NAME, command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, memberRouter.Help()...)
 }
 
 // GetAdminHelp returns the admin help for the banking system
 func (plugin *Plugin) GetAdminHelp() []string {
-	help := make([]string, 0, len(adminCommands[0].Options))
-
-	commandPrefix := adminCommands[0].Name
-	for _, command := range adminCommands[0].Options {
-		commandDescription := fmt.Sprintf("- **/%s %s**:  %s\n", commandPrefix, command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, adminRouter.Help()...)
 }
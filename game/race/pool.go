@@ -0,0 +1,87 @@
+package race
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HOUSE_CUT = 10 // Default percent of the betting pool kept by the house
+)
+
+// BettingPool is the pari-mutuel pool for a race. All bets are placed in a single pool, and
+// after the house takes its cut the rest of the pool is shared by those who bet on the winner,
+// in proportion to the amount they bet.
+type BettingPool struct {
+	Total    int                      // Total amount bet on the race
+	ByRacer  map[*RaceParticipant]int // Amount bet on each racer
+	HouseCut int                      // Percent of the pool kept by the house
+}
+
+// newBettingPool returns the betting pool for the bets placed on a race.
+func newBettingPool(betters []*RaceBetter, houseCut int) *BettingPool {
+	pool := &BettingPool{
+		ByRacer:  make(map[*RaceParticipant]int),
+		HouseCut: houseCut,
+	}
+	for _, better := range betters {
+		pool.Total += better.Amount
+		pool.ByRacer[better.Racer] += better.Amount
+	}
+	return pool
+}
+
+// Net returns the amount of the pool that is paid out to the winners after the house takes its cut.
+func (pool *BettingPool) Net() int {
+	return pool.Total * (100 - pool.HouseCut) / 100
+}
+
+// Odds returns the amount paid for each credit bet on the racer if the racer wins. Zero is
+// returned if no one has bet on the racer.
+func (pool *BettingPool) Odds(racer *RaceParticipant) float64 {
+	amount := pool.ByRacer[racer]
+	if amount == 0 {
+		return 0
+	}
+	return float64(pool.Net()) / float64(amount)
+}
+
+// FormatOdds returns the odds for the racer as shown on the race message.
+func (pool *BettingPool) FormatOdds(racer *RaceParticipant) string {
+	odds := pool.Odds(racer)
+	if odds == 0 {
+		return "no bets"
+	}
+	return fmt.Sprintf("%.2fx", odds)
+}
+
+// Payouts returns the amount paid to each better if the given racer wins, along with the amount
+// kept by the house. If no one bet on the winner, all bets are refunded and the house keeps nothing.
+func (pool *BettingPool) Payouts(betters []*RaceBetter, winner *RaceParticipant) (map[*RaceBetter]int, int) {
+	log.Trace("--> race.BettingPool.Payouts")
+	defer log.Trace("<-- race.BettingPool.Payouts")
+
+	payouts := make(map[*RaceBetter]int, len(betters))
+	winningBets := pool.ByRacer[winner]
+	if winningBets == 0 {
+		for _, better := range betters {
+			payouts[better] = better.Amount
+		}
+		return payouts, 0
+	}
+
+	net := pool.Net()
+	paid := 0
+	for _, better := range betters {
+		if better.Racer != winner {
+			continue
+		}
+		payout := net * better.Amount / winningBets
+		payouts[better] = payout
+		paid += payout
+	}
+
+	// Any credits lost to rounding are kept by the house.
+	return payouts, pool.Total - paid
+}
@@ -0,0 +1,55 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestBettingPool(t *testing.T) {
+	first := &RaceParticipant{}
+	second := &RaceParticipant{}
+	betters := []*RaceBetter{
+		{Member: &RaceMember{MemberID: "1"}, Racer: first, Amount: 100},
+		{Member: &RaceMember{MemberID: "2"}, Racer: first, Amount: 300},
+		{Member: &RaceMember{MemberID: "3"}, Racer: second, Amount: 600},
+	}
+	pool := newBettingPool(betters, 10)
+
+	if pool.Total != 1000 {
+		t.Errorf("expected a pool of 1000, got %d", pool.Total)
+	}
+	if pool.Net() != 900 {
+		t.Errorf("expected a net pool of 900, got %d", pool.Net())
+	}
+	if odds := pool.Odds(first); odds != 2.25 {
+		t.Errorf("expected odds of 2.25, got %.2f", odds)
+	}
+	if odds := pool.FormatOdds(&RaceParticipant{}); odds != "no bets" {
+		t.Errorf("expected no bets, got %s", odds)
+	}
+
+	payouts, houseTake := pool.Payouts(betters, first)
+	if payouts[betters[0]] != 225 || payouts[betters[1]] != 675 || payouts[betters[2]] != 0 {
+		t.Errorf("unexpected payouts %d, %d, %d", payouts[betters[0]], payouts[betters[1]], payouts[betters[2]])
+	}
+	if houseTake != 100 {
+		t.Errorf("expected the house to take 100, got %d", houseTake)
+	}
+}
+
+func TestBettingPoolNoWinningBets(t *testing.T) {
+	first := &RaceParticipant{}
+	second := &RaceParticipant{}
+	betters := []*RaceBetter{
+		{Member: &RaceMember{MemberID: "1"}, Racer: first, Amount: 100},
+		{Member: &RaceMember{MemberID: "2"}, Racer: first, Amount: 250},
+	}
+	pool := newBettingPool(betters, 10)
+
+	payouts, houseTake := pool.Payouts(betters, second)
+	if payouts[betters[0]] != 100 || payouts[betters[1]] != 250 {
+		t.Errorf("expected all bets to be refunded, got %d and %d", payouts[betters[0]], payouts[betters[1]])
+	}
+	if houseTake != 0 {
+		t.Errorf("expected the house to take nothing, got %d", houseTake)
+	}
+}
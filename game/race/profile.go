@@ -0,0 +1,258 @@
+package race
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DEFAULT_MOVEMENT_PROFILE = "special"
+)
+
+// MovementProfile determines how far a racer moves on each turn of a race. On each turn the
+// racer rolls a die between MinRoll and MaxRoll, and the roll is multiplied by the Multiplier
+// to get the distance moved. This may be modified by the profile:
+//   - OpeningMoves, if set, are the fixed distances moved on the turns at the start of the race.
+//   - RestEvery, if set, causes the racer to rest (not move) on every nth turn.
+//   - BurstChance is the percent chance the racer moves BurstMovement instead of rolling.
+type MovementProfile struct {
+	ID            primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID       string             `json:"guild_id" bson:"guild_id"`
+	Theme         string             `json:"theme" bson:"theme"`
+	Name          string             `json:"name" bson:"name"`
+	MinRoll       int                `json:"min_roll" bson:"min_roll"`
+	MaxRoll       int                `json:"max_roll" bson:"max_roll"`
+	Multiplier    int                `json:"multiplier" bson:"multiplier"`
+	OpeningMoves  []int              `json:"opening_moves" bson:"opening_moves"`
+	RestEvery     int                `json:"rest_every" bson:"rest_every"`
+	BurstChance   int                `json:"burst_chance" bson:"burst_chance"`
+	BurstMovement int                `json:"burst_movement" bson:"burst_movement"`
+}
+
+var (
+	// builtinProfiles are the movement profiles available to all guilds and themes. They may be
+	// overridden by a profile of the same name for a guild and theme.
+	builtinProfiles = map[string]*MovementProfile{
+		"veryfast": {
+			Name:       "veryfast",
+			MinRoll:    0,
+			MaxRoll:    7,
+			Multiplier: 2,
+		},
+		"fast": {
+			Name:       "fast",
+			MinRoll:    0,
+			MaxRoll:    4,
+			Multiplier: 3,
+		},
+		"slow": {
+			Name:       "slow",
+			MinRoll:    1,
+			MaxRoll:    3,
+			Multiplier: 3,
+		},
+		"steady": {
+			Name:       "steady",
+			MinRoll:    2,
+			MaxRoll:    2,
+			Multiplier: 3,
+		},
+		"abberant": {
+			Name:          "abberant",
+			MinRoll:       0,
+			MaxRoll:       2,
+			Multiplier:    3,
+			BurstChance:   9,
+			BurstMovement: 15,
+		},
+		"predator": {
+			Name:       "predator",
+			MinRoll:    2,
+			MaxRoll:    5,
+			Multiplier: 3,
+			RestEvery:  2,
+		},
+		"special": {
+			Name:         "special",
+			MinRoll:      0,
+			MaxRoll:      2,
+			Multiplier:   3,
+			OpeningMoves: []int{42, 0},
+		},
+	}
+)
+
+// GetMovementProfiles returns the movement profiles available to a guild and theme. This
+// includes the built-in profiles, along with any profiles defined for the guild and theme.
+func GetMovementProfiles(guildID string, theme string) map[string]*MovementProfile {
+	log.Trace("--> race.GetMovementProfiles")
+	defer log.Trace("<-- race.GetMovementProfiles")
+
+	profiles := make(map[string]*MovementProfile, len(builtinProfiles))
+	for name, profile := range builtinProfiles {
+		profiles[name] = profile
+	}
+
+	guildProfiles, err := readMovementProfiles(guildID, theme)
+	if err != nil {
+		return profiles
+	}
+	for _, profile := range guildProfiles {
+		profiles[profile.Name] = profile
+	}
+
+	return profiles
+}
+
+// getBuiltinProfile returns the built-in movement profile with the given name. If there isn't
+// one, then the default profile is returned.
+func getBuiltinProfile(name string) *MovementProfile {
+	profile, ok := builtinProfiles[name]
+	if !ok {
+		profile = builtinProfiles[DEFAULT_MOVEMENT_PROFILE]
+	}
+	return profile
+}
+
+// NewMovementProfile creates a movement profile for the guild and theme. The profile is validated
+// and saved to the database.
+func NewMovementProfile(profile *MovementProfile) error {
+	log.Trace("--> race.NewMovementProfile")
+	defer log.Trace("<-- race.NewMovementProfile")
+
+	err := profile.validate()
+	if err != nil {
+		return err
+	}
+	writeMovementProfile(profile)
+	log.WithFields(log.Fields{"guild": profile.GuildID, "theme": profile.Theme, "profile": profile.Name}).Info("set movement profile")
+
+	return nil
+}
+
+// RemoveMovementProfile removes a movement profile for the guild and theme. Built-in profiles
+// that are not overridden can't be removed.
+func RemoveMovementProfile(guildID string, theme string, name string) error {
+	log.Trace("--> race.RemoveMovementProfile")
+	defer log.Trace("<-- race.RemoveMovementProfile")
+
+	err := deleteMovementProfile(guildID, theme, name)
+	if err != nil {
+		return ErrProfileNotFound
+	}
+	log.WithFields(log.Fields{"guild": guildID, "theme": theme, "profile": name}).Info("remove movement profile")
+
+	return nil
+}
+
+// validate returns an error if the movement profile can't be used for racing.
+func (p *MovementProfile) validate() error {
+	if p.Name == "" {
+		return ErrInvalidProfile{"the profile must have a name"}
+	}
+	if p.MinRoll < 0 || p.MaxRoll < p.MinRoll {
+		return ErrInvalidProfile{"the minimum roll must be at least 0 and no more than the maximum roll"}
+	}
+	if p.Multiplier < 1 {
+		return ErrInvalidProfile{"the multiplier must be at least 1"}
+	}
+	if p.RestEvery < 0 || p.RestEvery == 1 {
+		return ErrInvalidProfile{"the racer may not rest every turn"}
+	}
+	if p.BurstChance < 0 || p.BurstChance > 100 || p.BurstMovement < 0 {
+		return ErrInvalidProfile{"the burst chance must be between 0 and 100, and the burst movement at least 0"}
+	}
+	for _, move := range p.OpeningMoves {
+		if move < 0 {
+			return ErrInvalidProfile{"opening moves must be at least 0"}
+		}
+	}
+	// Make sure the racer will eventually finish the race.
+	if p.MaxRoll == 0 && (p.BurstChance == 0 || p.BurstMovement == 0) {
+		return ErrInvalidProfile{"the racer must be able to move"}
+	}
+
+	return nil
+}
+
+// randomizer is the source of the random numbers used to move racers. Races use a seeded source
+// so the seed can be recorded with the results of the race.
+type randomizer interface {
+	Intn(n int) int
+}
+
+// globalRandomizer uses the shared source of random numbers.
+type globalRandomizer struct{}
+
+// Intn returns a random number in the range [0,n).
+func (globalRandomizer) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// Move returns the distance moved by a racer with the profile on the given turn.
+func (p *MovementProfile) Move(turn int) int {
+	return p.move(globalRandomizer{}, turn)
+}
+
+// move returns the distance moved by a racer with the profile on the given turn, using the
+// given source of random numbers.
+func (p *MovementProfile) move(rng randomizer, turn int) int {
+	if turn >= 0 && turn < len(p.OpeningMoves) {
+		return p.OpeningMoves[turn]
+	}
+	if p.RestEvery > 0 && turn%p.RestEvery == 0 {
+		return 0
+	}
+	if p.BurstChance > 0 && rng.Intn(100) >= 100-p.BurstChance {
+		return p.BurstMovement
+	}
+	return (p.MinRoll + rng.Intn(p.MaxRoll-p.MinRoll+1)) * p.Multiplier
+}
+
+// String returns a description of the movement profile.
+func (p *MovementProfile) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d-%d x%d", p.MinRoll, p.MaxRoll, p.Multiplier))
+	if len(p.OpeningMoves) > 0 {
+		sb.WriteString(", opening " + formatOpeningMoves(p.OpeningMoves))
+	}
+	if p.RestEvery > 0 {
+		sb.WriteString(fmt.Sprintf(", rests every %d turns", p.RestEvery))
+	}
+	if p.BurstChance > 0 {
+		sb.WriteString(fmt.Sprintf(", %d%% burst of %d", p.BurstChance, p.BurstMovement))
+	}
+	return sb.String()
+}
+
+// parseOpeningMoves parses a comma-separated list of opening moves.
+func parseOpeningMoves(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, ",")
+	moves := make([]int, 0, len(fields))
+	for _, field := range fields {
+		move, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, ErrInvalidProfile{"opening moves must be a comma-separated list of numbers"}
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// formatOpeningMoves returns the opening moves as a comma-separated list.
+func formatOpeningMoves(moves []int) string {
+	s := make([]string, 0, len(moves))
+	for _, move := range moves {
+		s = append(s, strconv.Itoa(move))
+	}
+	return strings.Join(s, ",")
+}
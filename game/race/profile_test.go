@@ -0,0 +1,73 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestBuiltinProfiles(t *testing.T) {
+	for name, profile := range builtinProfiles {
+		if err := profile.validate(); err != nil {
+			t.Errorf("built-in profile %s is invalid: %s", name, err)
+		}
+	}
+}
+
+func TestProfileMove(t *testing.T) {
+	profile := &MovementProfile{Name: "test", MinRoll: 1, MaxRoll: 3, Multiplier: 2, OpeningMoves: []int{10}, RestEvery: 3}
+	if move := profile.Move(0); move != 10 {
+		t.Errorf("expected opening move of 10, got %d", move)
+	}
+	if move := profile.Move(3); move != 0 {
+		t.Errorf("expected the racer to rest on turn 3, got %d", move)
+	}
+	for turn := 1; turn < 100; turn++ {
+		if turn%3 == 0 {
+			continue
+		}
+		move := profile.Move(turn)
+		if move < 2 || move > 6 || move%2 != 0 {
+			t.Errorf("unexpected move of %d on turn %d", move, turn)
+		}
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *MovementProfile
+		valid   bool
+	}{
+		{"valid", &MovementProfile{Name: "a", MinRoll: 0, MaxRoll: 2, Multiplier: 1}, true},
+		{"no name", &MovementProfile{MinRoll: 0, MaxRoll: 2, Multiplier: 1}, false},
+		{"min above max", &MovementProfile{Name: "a", MinRoll: 3, MaxRoll: 2, Multiplier: 1}, false},
+		{"no multiplier", &MovementProfile{Name: "a", MinRoll: 0, MaxRoll: 2}, false},
+		{"always rests", &MovementProfile{Name: "a", MinRoll: 0, MaxRoll: 2, Multiplier: 1, RestEvery: 1}, false},
+		{"never moves", &MovementProfile{Name: "a", MinRoll: 0, MaxRoll: 0, Multiplier: 1}, false},
+		{"only bursts", &MovementProfile{Name: "a", MinRoll: 0, MaxRoll: 0, Multiplier: 1, BurstChance: 10, BurstMovement: 5}, true},
+	}
+	for _, test := range tests {
+		err := test.profile.validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestParseOpeningMoves(t *testing.T) {
+	moves, err := parseOpeningMoves(" 42, 0 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 2 || moves[0] != 42 || moves[1] != 0 {
+		t.Errorf("unexpected opening moves %v", moves)
+	}
+	if formatOpeningMoves(moves) != "42,0" {
+		t.Errorf("unexpected formatted opening moves %s", formatOpeningMoves(moves))
+	}
+	if _, err := parseOpeningMoves("1,x"); err == nil {
+		t.Error("expected an error parsing invalid opening moves")
+	}
+}
@@ -1,8 +1,11 @@
 package race
 
 import (
+	"fmt"
 	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,6 +13,10 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	TRACK_LENGTH = 60
+)
+
 var (
 	currentRaces = make(map[string]*Race)
 	raceLock     = sync.Mutex{}
@@ -20,24 +27,29 @@ var (
 // betters on the outcome of the race.
 type Race struct {
 	GuildID     string                       // Guild (server) on which the race is taking place
+	StartTime   time.Time                    // Time at which the race was started
+	Seed        int64                        // Seed for the random numbers used to run the race
 	Racers      []*RaceParticipant           // The list of participants who are racing
 	Betters     []*RaceBetter                // The list of members who are betting on the outcome of the race
 	RaceLegs    []*RaceLeg                   // The list of legs in the race
 	RaceResult  *RaceResult                  // The results of the race
 	interaction *discordgo.InteractionCreate // Interaction used in sending message updates
 	config      *Config                      // Race configuration (avoids having to read from the database)
+	stage       string                       // Stage of the race, saved so the race may be cleaned up after a restart
+	betsClose   time.Time                    // Time at which betting on the race closes
 	mutex       sync.Mutex                   // Lock used to synchronize access to the race
 }
 
 // RaceResults is the final results of the race. This includes the winner, 2nd place, and 3rd place finishers, as
 // well as the speed at which they finished.
 type RaceResult struct {
-	Win       *RaceParticipant // First place in the race
-	Place     *RaceParticipant // Second place in the race
-	Show      *RaceParticipant // Third place in the race
-	WinTime   float64          // Speed for the winner in the race
-	PlaceTime float64          // Speed for the 2nd plalce finisher in the race
-	ShowTime  float64          // Speed for the 3rd place finisher in the race
+	Win       *RaceParticipant   // First place in the race
+	Place     *RaceParticipant   // Second place in the race
+	Show      *RaceParticipant   // Third place in the race
+	WinTime   float64            // Speed for the winner in the race
+	PlaceTime float64            // Speed for the 2nd plalce finisher in the race
+	ShowTime  float64            // Speed for the 3rd place finisher in the race
+	Finishers []*RaceParticipant // All participants in the order they finished the race
 }
 
 // RaceLeg is a single leg in a race. This covers the movement for all racers during the given turn.
@@ -59,12 +71,14 @@ type RaceParticipantPosition struct {
 type RaceParticipant struct {
 	Member *RaceMember // Member who is racing
 	Racer  *Racer      // Racer assigned to the member
+	Owned  *OwnedRacer // Racer owned by the member, if they are racing with their own racer
 }
 
 // RaceBetter is a member who is betting on the outcome of the race.
 type RaceBetter struct {
 	Member *RaceMember      // Member who is betting on the outcome of the the race
 	Racer  *RaceParticipant // Racer on which the member is betting
+	Amount int              // Amount the member bet on the racer
 }
 
 // GetRace gets the race for the guild. If a race isn't in progress, then a new one is created.
@@ -82,6 +96,28 @@ func GetRace(guildID string) *Race {
 	return race
 }
 
+// NewRace creates a new race for the guild. An error is returned if a race is already in progress
+// or if not enough time has passed since the last race ended.
+func NewRace(guildID string) (*Race, error) {
+	log.Trace("--> race.NewRace")
+	defer log.Trace("<-- race.NewRace")
+
+	raceLock.Lock()
+	defer raceLock.Unlock()
+
+	if currentRaces[guildID] != nil {
+		return nil, ErrRaceInProgress
+	}
+
+	config := GetConfig(guildID)
+	nextRace := config.LastRaceEnded.Add(config.WaitBetweenRaces)
+	if time.Now().Before(nextRace) {
+		return nil, ErrRaceTooSoon{RemainingTime: time.Until(nextRace)}
+	}
+
+	return newRace(guildID), nil
+}
+
 // newRace creates a new race for the guild.
 func newRace(guildID string) *Race {
 	log.Trace("--> race.newRace")
@@ -90,10 +126,12 @@ func newRace(guildID string) *Race {
 	config := GetConfig(guildID)
 	race := &Race{
 		GuildID:     guildID,
+		StartTime:   time.Now(),
 		Racers:      make([]*RaceParticipant, 0, 10),
 		Betters:     make([]*RaceBetter, 0, 10),
 		interaction: nil,
 		config:      config,
+		stage:       RACE_PLANNING,
 		mutex:       sync.Mutex{},
 	}
 	currentRaces[guildID] = race
@@ -103,40 +141,154 @@ func newRace(guildID string) *Race {
 }
 
 // newRaceBetter returns a new better for a race.
-func newRaceBetter(member *RaceMember, racer *RaceParticipant) *RaceBetter {
+func newRaceBetter(member *RaceMember, racer *RaceParticipant, amount int) *RaceBetter {
 	log.Trace("--> race.newRaceBetter")
 	defer log.Trace("<-- race.newRaceBetter")
 
 	raceBetter := &RaceBetter{
 		Member: member,
 		Racer:  racer,
+		Amount: amount,
 	}
 
 	return raceBetter
 }
 
-// AddRacer adds a race partipant to the given race.
-func (r *Race) AddRacer(raceParticipant *RaceParticipant) {
+// getID returns the ID of the race, which identifies the race in the components used to bet on it.
+func (r *Race) getID() string {
+	return strconv.FormatInt(r.StartTime.UnixMilli(), 10)
+}
+
+// getRacer returns the participant in the race for the member, or `nil` if the member isn't racing.
+func (r *Race) getRacer(memberID string) *RaceParticipant {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, racer := range r.Racers {
+		if racer.Member.MemberID == memberID {
+			return racer
+		}
+	}
+	return nil
+}
+
+// getRaceMember returns the race member for the member. If the member is racing, the race member of
+// their participant is returned, so that their race and bet statistics are kept in a single copy of
+// the race member and one doesn't overwrite the other when the race is paid out.
+func (r *Race) getRaceMember(memberID string) *RaceMember {
+	if racer := r.getRacer(memberID); racer != nil {
+		return racer.Member
+	}
+	return GetRaceMember(r.GuildID, memberID)
+}
+
+// AddRacer adds a race partipant to the given race. An error is returned if the member
+// has already joined the race or the race is full.
+func (r *Race) AddRacer(raceParticipant *RaceParticipant) error {
 	log.Trace("--> race.Race.AddRacer")
 	defer log.Trace("<-- race.Race.AddRacer")
 
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if r.stage != RACE_PLANNING {
+		return ErrRaceAlreadyStarted
+	}
+	if slices.ContainsFunc(r.Racers, func(racer *RaceParticipant) bool {
+		return racer.Member.MemberID == raceParticipant.Member.MemberID
+	}) {
+		return ErrAlreadyJoinedRace
+	}
+	if len(r.Racers) >= r.config.MaxNumRacers {
+		return ErrRaceFull
+	}
+
 	r.Racers = append(r.Racers, raceParticipant)
+	r.saveState()
 	log.WithFields(log.Fields{"guild": r.GuildID, "racer": raceParticipant.Member.MemberID}).Info("add racer to current race")
+
+	return nil
 }
 
-// Adds a better for the given race.
-func (race *Race) AddBetter(better *RaceBetter) {
+// Adds a better for the given race. An error is returned if betting is not open or the member
+// has already placed a bet on the race.
+func (race *Race) AddBetter(better *RaceBetter) error {
 	log.Trace("--> race.Race.AddBetter")
 	defer log.Trace("<-- race.Race.AddBetter")
 
 	race.mutex.Lock()
 	defer race.mutex.Unlock()
 
+	if race.stage != RACE_BETTING {
+		return ErrBettingClosed
+	}
+	if slices.ContainsFunc(race.Betters, func(b *RaceBetter) bool {
+		return b.Member.MemberID == better.Member.MemberID
+	}) {
+		return ErrAlreadyBet
+	}
+
 	race.Betters = append(race.Betters, better)
-	log.WithFields(log.Fields{"guild": race.GuildID, "better": better.Member.MemberID}).Info("add better to current race")
+	race.saveState()
+	log.WithFields(log.Fields{"guild": race.GuildID, "better": better.Member.MemberID, "amount": better.Amount}).Info("add better to current race")
+
+	return nil
+}
+
+// removeBetter removes the better from the race, such as when the member is unable to pay for their bet.
+func (race *Race) removeBetter(better *RaceBetter) {
+	log.Trace("--> race.Race.removeBetter")
+	defer log.Trace("<-- race.Race.removeBetter")
+
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	race.Betters = slices.DeleteFunc(race.Betters, func(b *RaceBetter) bool {
+		return b == better
+	})
+	race.saveState()
+	log.WithFields(log.Fields{"guild": race.GuildID, "better": better.Member.MemberID}).Info("remove better from current race")
+}
+
+// Pool returns the current betting pool for the race.
+func (race *Race) Pool() *BettingPool {
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	return newBettingPool(race.Betters, race.config.HouseCut)
+}
+
+// setStage updates the stage of the race and saves the race state to the database.
+func (race *Race) setStage(stage string) {
+	log.Trace("--> race.Race.setStage")
+	defer log.Trace("<-- race.Race.setStage")
+
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	race.stage = stage
+	race.saveState()
+	log.WithFields(log.Fields{"guild": race.GuildID, "stage": stage}).Debug("set race stage")
+}
+
+// availableRacers returns the racers that have not yet been assigned to a participant in the race.
+// If all racers have been assigned, then the full list is returned.
+func (race *Race) availableRacers(racers []*Racer) []*Racer {
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	available := make([]*Racer, 0, len(racers))
+	for _, racer := range racers {
+		if !slices.ContainsFunc(race.Racers, func(participant *RaceParticipant) bool {
+			return participant.Racer.Emoji == racer.Emoji
+		}) {
+			available = append(available, racer)
+		}
+	}
+	if len(available) == 0 {
+		return racers
+	}
+	return available
 }
 
 // RunRace runs a race, calculating the results of each leg of the race and the
@@ -148,6 +300,12 @@ func (race *Race) RunRace(trackLength int) {
 	race.mutex.Lock()
 	defer race.mutex.Unlock()
 
+	// The seed is kept so it can be saved with the results of the race.
+	if race.Seed == 0 {
+		race.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(race.Seed))
+
 	// Create the initial starting positions and add them to an initial race leg
 	raceLeg := &RaceLeg{
 		ParticipantPositions: make([]*RaceParticipantPosition, 0, len(race.Racers)),
@@ -155,7 +313,7 @@ func (race *Race) RunRace(trackLength int) {
 	for _, racer := range race.Racers {
 		participantPosition := &RaceParticipantPosition{
 			RaceParticipant: racer,
-			Position:        100, // TODO: get the current position for the racer
+			Position:        trackLength,
 		}
 		raceLeg.ParticipantPositions = append(raceLeg.ParticipantPositions, participantPosition)
 	}
@@ -176,7 +334,7 @@ func (race *Race) RunRace(trackLength int) {
 		// Run the new race leg
 		stillRacing = false
 		for _, previousPosition := range previousLeg.ParticipantPositions {
-			newPosition := Move(previousPosition, turn)
+			newPosition := move(rng, previousPosition, turn)
 			newRaceLeg.ParticipantPositions = append(newRaceLeg.ParticipantPositions, newPosition)
 			if !newPosition.Finished {
 				stillRacing = true
@@ -188,27 +346,34 @@ func (race *Race) RunRace(trackLength int) {
 		log.WithFields(log.Fields{"guildID": race.GuildID, "turn": turn}).Trace("run race leg")
 	}
 
-	// sort the participants in the last race leg (previousLeg)
-	sort.Slice(previousLeg.ParticipantPositions, func(i, j int) bool {
-		if previousLeg.ParticipantPositions[i].Speed == previousLeg.ParticipantPositions[j].Speed {
-			return rand.Intn(2) == 0
+	// sort the participants in the last race leg (previousLeg). A copy is sorted so the lanes
+	// remain in the same order when the legs are shown.
+	finishers := slices.Clone(previousLeg.ParticipantPositions)
+	sort.Slice(finishers, func(i, j int) bool {
+		if finishers[i].Speed == finishers[j].Speed {
+			return rng.Intn(2) == 0
 		}
-		return previousLeg.ParticipantPositions[i].Speed < previousLeg.ParticipantPositions[j].Speed
+		return finishers[i].Speed < finishers[j].Speed
 	})
 
 	// Calculate the winners of the race and save in the results
-	race.RaceResult = &RaceResult{}
-	if len(previousLeg.ParticipantPositions) > 0 {
-		race.RaceResult.Win = previousLeg.ParticipantPositions[0].RaceParticipant
-		race.RaceResult.WinTime = previousLeg.ParticipantPositions[0].Speed
+	race.RaceResult = &RaceResult{
+		Finishers: make([]*RaceParticipant, 0, len(finishers)),
+	}
+	for _, finisher := range finishers {
+		race.RaceResult.Finishers = append(race.RaceResult.Finishers, finisher.RaceParticipant)
 	}
-	if len(previousLeg.ParticipantPositions) > 1 {
-		race.RaceResult.Show = previousLeg.ParticipantPositions[1].RaceParticipant
-		race.RaceResult.ShowTime = previousLeg.ParticipantPositions[1].Speed
+	if len(finishers) > 0 {
+		race.RaceResult.Win = finishers[0].RaceParticipant
+		race.RaceResult.WinTime = finishers[0].Speed
 	}
-	if len(previousLeg.ParticipantPositions) > 2 {
-		race.RaceResult.Place = previousLeg.ParticipantPositions[2].RaceParticipant
-		race.RaceResult.PlaceTime = previousLeg.ParticipantPositions[2].Speed
+	if len(finishers) > 1 {
+		race.RaceResult.Place = finishers[1].RaceParticipant
+		race.RaceResult.PlaceTime = finishers[1].Speed
+	}
+	if len(finishers) > 2 {
+		race.RaceResult.Show = finishers[2].RaceParticipant
+		race.RaceResult.ShowTime = finishers[2].Speed
 	}
 }
 
@@ -218,6 +383,7 @@ func (r *Race) End() {
 	defer raceLock.Unlock()
 
 	delete(currentRaces, r.GuildID)
+	deleteRaceState(r.GuildID)
 
 	config := GetConfig(r.GuildID)
 	config.LastRaceEnded = time.Now()
@@ -235,6 +401,7 @@ func ResetRace(guildID string) {
 	defer raceLock.Unlock()
 
 	delete(currentRaces, guildID)
+	deleteRaceState(guildID)
 	log.WithFields(log.Fields{"guild": guildID}).Info("reset race")
 }
 
@@ -254,8 +421,37 @@ func newRaceParitipcant(member *RaceMember, racers []*Racer) *RaceParticipant {
 	return participant
 }
 
+// newOwnedRaceParticipant returns a new participant for a race who is racing with their own racer.
+func newOwnedRaceParticipant(member *RaceMember, owned *OwnedRacer, profiles map[string]*MovementProfile) *RaceParticipant {
+	log.Trace("--> race.newOwnedRaceParticipant")
+	defer log.Trace("<-- race.newOwnedRaceParticipant")
+
+	participant := &RaceParticipant{
+		Member: member,
+		Racer:  owned.getRacer(profiles),
+		Owned:  owned,
+	}
+	log.WithFields(log.Fields{"guild": member.GuildID, "member": member.MemberID, "racer": owned.Name}).Debug("new owned race participant")
+
+	return participant
+}
+
+// getName returns the name shown for the participant in the race. If the member is racing
+// with their own racer, then the name of the racer is included.
+func (p *RaceParticipant) getName() string {
+	if p.Owned == nil {
+		return p.Member.getName()
+	}
+	return fmt.Sprintf("%s (%s)", p.Member.getName(), p.Owned.Name)
+}
+
 // Move returns the new race position for a particpant based on the previous position and the current turn.
 func Move(previousPosition *RaceParticipantPosition, turn int) *RaceParticipantPosition {
+	return move(globalRandomizer{}, previousPosition, turn)
+}
+
+// move returns the new race position for a participant, using the given source of random numbers.
+func move(rng randomizer, previousPosition *RaceParticipantPosition, turn int) *RaceParticipantPosition {
 	log.Trace("-->race.RaceParticpant.Move")
 	defer log.Trace("<-- race.RaceParticpant.Move")
 
@@ -263,23 +459,26 @@ func Move(previousPosition *RaceParticipantPosition, turn int) *RaceParticipantP
 	if previousPosition.Position <= 0 {
 		newPosition := &RaceParticipantPosition{
 			RaceParticipant: previousPosition.RaceParticipant,
+			Position:        0,
+			Turn:            previousPosition.Turn,
 			Finished:        true,
 			Speed:           previousPosition.Speed,
 		}
 		return newPosition
 	}
 
-	movement := previousPosition.RaceParticipant.Racer.calculateMovement(turn)
+	movement := previousPosition.RaceParticipant.Racer.movement(rng, turn)
 	newPosition := &RaceParticipantPosition{
 		RaceParticipant: previousPosition.RaceParticipant,
-		Position:        previousPosition.Position - movement,
-		Movement:        previousPosition.Movement,
+		Position:        max(previousPosition.Position-movement, 0),
+		Movement:        movement,
 		Turn:            previousPosition.Turn + 1,
 		Finished:        false,
 	}
 	newPosition.Speed = float64(newPosition.Turn)
 	if newPosition.Position <= 0 {
-		newPosition.Speed += float64(previousPosition.Position) / float64(movement)
+		// Only count the part of the turn needed to cross the finish line
+		newPosition.Speed = float64(previousPosition.Turn) + float64(previousPosition.Position)/float64(movement)
 	}
 
 	return newPosition
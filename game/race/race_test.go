@@ -53,8 +53,68 @@ func TestGetRace(t *testing.T) {
 	race.AddRacer(racer1)
 	race.AddRacer(racer2)
 
-	race.RunRace(60)
+	race.RunRace(TRACK_LENGTH)
+	if race.RaceResult == nil || race.RaceResult.Win == nil || race.RaceResult.Place == nil {
+		t.Error("expected a winner and a second place finisher")
+	} else if race.RaceResult.WinTime > race.RaceResult.PlaceTime {
+		t.Error("expected the winner to finish before the second place finisher")
+	}
+
+	filter := bson.M{"guild_id": "123", "member_id": "456"}
+	db.Delete(RACE_MEMBER_COLLECTION, filter)
+	filter = bson.M{"guild_id": "123", "member_id": "789"}
+	db.Delete(RACE_MEMBER_COLLECTION, filter)
+	filter = bson.M{"guild_id": "123", "theme": "clash"}
+	db.DeleteMany(RACER_COLLECTION, filter)
+	filter = bson.M{"guild_id": "123"}
+	db.Delete(RACE_CONFIG_COLLECTION, filter)
+	db.Delete(RACE_STATE_COLLECTION, filter)
+}
+
+func TestRaceAndBet(t *testing.T) {
+	race := newRace("123")
+	racers := GetRacers("123", "clash")
+	if len(racers) < 2 {
+		t.Fatal("expected at least 2 racers")
+	}
+
+	racer1 := &RaceParticipant{
+		Member: GetRaceMember("123", "456"),
+		Racer:  racers[0],
+	}
+	racer2 := &RaceParticipant{
+		Member: GetRaceMember("123", "789"),
+		Racer:  racers[1],
+	}
+	race.AddRacer(racer1)
+	race.AddRacer(racer2)
+
+	// The member who is racing also bets on themself
+	better := race.getRaceMember("456")
+	if better != racer1.Member {
+		t.Error("expected the better to share the race member of the racer")
+	}
+	race.setStage(RACE_BETTING)
+	err := race.AddBetter(newRaceBetter(better, racer1, 100))
+	if err != nil {
+		t.Errorf("unexpected error adding the better: %v", err)
+	}
+
+	race.RaceResult = &RaceResult{Win: racer1, Place: racer2}
+	payoutRace(race)
 
+	member := readRaceMember("123", "456")
+	if member == nil {
+		t.Fatal("expected the race member to be saved")
+	}
+	if member.RacesWon != 1 || member.TotalRaces != 1 {
+		t.Errorf("expected 1 race won out of 1 race, got %d out of %d", member.RacesWon, member.TotalRaces)
+	}
+	if member.BetsWon != 1 {
+		t.Errorf("expected 1 bet won, got %d", member.BetsWon)
+	}
+
+	delete(currentRaces, "123")
 	filter := bson.M{"guild_id": "123", "member_id": "456"}
 	db.Delete(RACE_MEMBER_COLLECTION, filter)
 	filter = bson.M{"guild_id": "123", "member_id": "789"}
@@ -63,4 +123,34 @@ func TestGetRace(t *testing.T) {
 	db.DeleteMany(RACER_COLLECTION, filter)
 	filter = bson.M{"guild_id": "123"}
 	db.Delete(RACE_CONFIG_COLLECTION, filter)
+	db.Delete(RACE_STATE_COLLECTION, filter)
+}
+
+func TestMove(t *testing.T) {
+	participant := &RaceParticipant{
+		Member: &RaceMember{GuildID: "123", MemberID: "456"},
+		Racer:  &Racer{MovementSpeed: "steady"},
+	}
+	position := &RaceParticipantPosition{
+		RaceParticipant: participant,
+		Position:        10,
+	}
+
+	position = Move(position, 1)
+	if position.Position != 4 || position.Movement != 6 || position.Finished {
+		t.Errorf("expected position 4 with movement 6, got position %d with movement %d", position.Position, position.Movement)
+	}
+
+	position = Move(position, 2)
+	if position.Position != 0 {
+		t.Errorf("expected position 0, got %d", position.Position)
+	}
+	if position.Speed <= 1 || position.Speed >= 2 {
+		t.Errorf("expected a speed between 1 and 2, got %.2f", position.Speed)
+	}
+
+	position = Move(position, 3)
+	if !position.Finished {
+		t.Error("expected the racer to have finished")
+	}
 }
@@ -1,8 +1,6 @@
 package race
 
 import (
-	"math/rand"
-
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,16 +13,25 @@ type Racer struct {
 	Theme         string             `json:"theme" bson:"theme"`
 	Emoji         string             `json:"emoji" bson:"emoji"`
 	MovementSpeed string             `json:"movement_speed" bson:"movement_speed"`
+	profile       *MovementProfile   `json:"-" bson:"-"`
 }
 
-// GetRacers returns the list of chracters that may be assigned to a member during a race.
+// GetRacers returns the list of chracters that may be assigned to a member during a race. The
+// racers for the default theme are created if they don't exist, but other themes may have no racers.
 func GetRacers(guildID string, themeName string) []*Racer {
 	log.Trace("--> race.GetRacers")
 	defer log.Trace("<-- race.GetRacers")
 
 	characters, err := getRacers(guildID, themeName)
 	if err != nil {
+		if themeName != DEFAULT_THEME {
+			return nil
+		}
 		characters = newRacers(guildID)
+		profiles := GetMovementProfiles(guildID, themeName)
+		for _, character := range characters {
+			character.profile = profiles[character.MovementSpeed]
+		}
 	}
 	return characters
 }
@@ -42,10 +49,58 @@ func getRacers(guildID string, themeName string) ([]*Racer, error) {
 		return nil, err
 	}
 
+	profiles := GetMovementProfiles(guildID, themeName)
+	for _, r := range racer {
+		r.profile = profiles[r.MovementSpeed]
+	}
+
 	log.WithFields(log.Fields{"guild": guildID, "theme": themeName, "count": len(racer)}).Info("read racers")
 	return racer, nil
 }
 
+// NewRacer adds a racer with the given emoji and movement profile to the theme for the guild.
+func NewRacer(guildID string, themeName string, emoji string, movement string) (*Racer, error) {
+	log.Trace("--> race.NewRacer")
+	defer log.Trace("<-- race.NewRacer")
+
+	profiles := GetMovementProfiles(guildID, themeName)
+	profile, ok := profiles[movement]
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+	for _, racer := range GetRacers(guildID, themeName) {
+		if racer.Emoji == emoji {
+			return nil, ErrRacerExists
+		}
+	}
+
+	racer := &Racer{
+		GuildID:       guildID,
+		Theme:         themeName,
+		Emoji:         emoji,
+		MovementSpeed: movement,
+		profile:       profile,
+	}
+	writeRacer(racer)
+	log.WithFields(log.Fields{"guild": guildID, "theme": themeName, "emoji": emoji, "movement": movement}).Info("added racer")
+
+	return racer, nil
+}
+
+// RemoveRacer removes the racer with the given emoji from the theme for the guild.
+func RemoveRacer(guildID string, themeName string, emoji string) error {
+	log.Trace("--> race.RemoveRacer")
+	defer log.Trace("<-- race.RemoveRacer")
+
+	err := deleteRacer(guildID, themeName, emoji)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "theme": themeName, "emoji": emoji}).Info("removed racer")
+
+	return nil
+}
+
 // newRacers creates a new list of characters for the guild. The list is saved to
 // the database.
 func newRacers(guildID string) []*Racer {
@@ -276,40 +331,17 @@ func newRacers(guildID string) []*Racer {
 
 // calculateMovement calculates the distance a racer moves on a given turn
 func (r *Racer) calculateMovement(currentTurn int) int {
+	return r.movement(globalRandomizer{}, currentTurn)
+}
+
+// movement calculates the distance a racer moves on a given turn, using the given source of
+// random numbers.
+func (r *Racer) movement(rng randomizer, currentTurn int) int {
 	log.Trace("--> calculateMovement")
 	defer log.Trace("<-- calculateMovement")
 
-	switch r.MovementSpeed {
-	case "veryfast":
-		return rand.Intn(8) * 2
-	case "fast":
-		return rand.Intn(5) * 3
-	case "slow":
-		return (rand.Intn(3) + 1) * 3
-	case "steady":
-		return 2 * 3
-	case "abberant":
-		chance := rand.Intn(100)
-		if chance > 90 {
-			return 5 * 3
-		}
-		return rand.Intn(3) * 3
-	case "predator":
-		if currentTurn%2 == 0 {
-			return 0
-		} else {
-			return (rand.Intn(4) + 2) * 3
-		}
-	case "special":
-		fallthrough
-	default:
-		switch currentTurn {
-		case 0:
-			return 14 * 3
-		case 1:
-			return 0
-		default:
-			return rand.Intn(3) * 3
-		}
+	if r.profile == nil {
+		r.profile = getBuiltinProfile(r.MovementSpeed)
 	}
+	return r.profile.move(rng, currentTurn)
 }
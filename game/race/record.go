@@ -0,0 +1,174 @@
+package race
+
+import (
+	"time"
+
+	"github.com/rbrabson/goblin/guild"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RaceRecord is the persisted outcome of a race. It includes every leg of the race, so the
+// race can be replayed.
+type RaceRecord struct {
+	ID           primitive.ObjectID       `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID      string                   `json:"guild_id" bson:"guild_id"`
+	Number       int                      `json:"number" bson:"number"`
+	Theme        string                   `json:"theme" bson:"theme"`
+	StartTime    time.Time                `json:"start_time" bson:"start_time"`
+	EndTime      time.Time                `json:"end_time" bson:"end_time"`
+	Seed         int64                    `json:"seed" bson:"seed"`
+	TrackLength  int                      `json:"track_length" bson:"track_length"`
+	StartingLine string                   `json:"starting_line" bson:"starting_line"`
+	EndingLine   string                   `json:"ending_line" bson:"ending_line"`
+	Participants []*RaceRecordParticipant `json:"participants" bson:"participants"`
+	Legs         [][]int                  `json:"legs" bson:"legs"` // Position of each participant, in the order of Participants, for each leg
+	Bets         []*RaceRecordBet         `json:"bets" bson:"bets"`
+	Pool         int                      `json:"pool" bson:"pool"`
+	HouseTake    int                      `json:"house_take" bson:"house_take"`
+}
+
+// RaceRecordParticipant is a member who ran in a recorded race.
+type RaceRecordParticipant struct {
+	MemberID      string  `json:"member_id" bson:"member_id"`
+	Name          string  `json:"name" bson:"name"`
+	Emoji         string  `json:"emoji" bson:"emoji"`
+	MovementSpeed string  `json:"movement_speed" bson:"movement_speed"`
+	OwnedRacer    string  `json:"owned_racer,omitempty" bson:"owned_racer,omitempty"`
+	Finish        int     `json:"finish" bson:"finish"`
+	Time          float64 `json:"time" bson:"time"`
+	Prize         int     `json:"prize" bson:"prize"`
+}
+
+// RaceRecordBet is a bet placed on a recorded race.
+type RaceRecordBet struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	RacerID  string `json:"racer_id" bson:"racer_id"`
+	Amount   int    `json:"amount" bson:"amount"`
+	Payout   int    `json:"payout" bson:"payout"`
+}
+
+// newRaceRecord creates the record of a race from its results and payouts.
+func newRaceRecord(race *Race, payouts *racePayouts) *RaceRecord {
+	log.Trace("--> race.newRaceRecord")
+	defer log.Trace("<-- race.newRaceRecord")
+
+	race.mutex.Lock()
+	defer race.mutex.Unlock()
+
+	record := &RaceRecord{
+		GuildID:      race.GuildID,
+		Number:       countRaceRecords(race.GuildID) + 1,
+		Theme:        race.config.Theme,
+		StartTime:    race.StartTime,
+		EndTime:      time.Now(),
+		Seed:         race.Seed,
+		TrackLength:  TRACK_LENGTH,
+		StartingLine: race.config.StartingLine,
+		EndingLine:   race.config.EndingLine,
+		Participants: make([]*RaceRecordParticipant, 0, len(race.Racers)),
+		Legs:         make([][]int, 0, len(race.RaceLegs)),
+		Bets:         make([]*RaceRecordBet, 0, len(race.Betters)),
+	}
+
+	var finalLeg *RaceLeg
+	if len(race.RaceLegs) > 0 {
+		finalLeg = race.RaceLegs[len(race.RaceLegs)-1]
+	}
+	for idx, racer := range race.Racers {
+		participant := &RaceRecordParticipant{
+			MemberID:      racer.Member.MemberID,
+			Name:          racer.Member.getName(),
+			Emoji:         racer.Racer.Emoji,
+			MovementSpeed: racer.Racer.MovementSpeed,
+			Prize:         payouts.prizes[racer.Member.MemberID],
+		}
+		if racer.Owned != nil {
+			participant.OwnedRacer = racer.Owned.Name
+		}
+		if race.RaceResult != nil {
+			for finish, finisher := range race.RaceResult.Finishers {
+				if finisher == racer {
+					participant.Finish = finish + 1
+				}
+			}
+		}
+		if finalLeg != nil && idx < len(finalLeg.ParticipantPositions) {
+			participant.Time = finalLeg.ParticipantPositions[idx].Speed
+		}
+		record.Participants = append(record.Participants, participant)
+	}
+
+	for _, leg := range race.RaceLegs {
+		positions := make([]int, 0, len(leg.ParticipantPositions))
+		for _, position := range leg.ParticipantPositions {
+			positions = append(positions, position.Position)
+		}
+		record.Legs = append(record.Legs, positions)
+	}
+
+	for _, better := range race.Betters {
+		bet := &RaceRecordBet{
+			MemberID: better.Member.MemberID,
+			RacerID:  better.Racer.Member.MemberID,
+			Amount:   better.Amount,
+			Payout:   payouts.betWinnings[better],
+		}
+		record.Bets = append(record.Bets, bet)
+	}
+	if payouts.pool != nil {
+		record.Pool = payouts.pool.Total
+	}
+	record.HouseTake = payouts.houseTake
+
+	return record
+}
+
+// Winner returns the participant who won the recorded race, or `nil` if there isn't one.
+func (record *RaceRecord) Winner() *RaceRecordParticipant {
+	for _, participant := range record.Participants {
+		if participant.Finish == 1 {
+			return participant
+		}
+	}
+	return nil
+}
+
+// getLegs rebuilds the legs of a recorded race so that it may be shown again.
+func (record *RaceRecord) getLegs() []*RaceLeg {
+	participants := make([]*RaceParticipant, 0, len(record.Participants))
+	for _, p := range record.Participants {
+		participant := &RaceParticipant{
+			Member: &RaceMember{
+				GuildID:     record.GuildID,
+				MemberID:    p.MemberID,
+				guildMember: &guild.Member{GuildID: record.GuildID, MemberID: p.MemberID, Name: p.Name},
+			},
+			Racer: &Racer{Emoji: p.Emoji, MovementSpeed: p.MovementSpeed},
+		}
+		if p.OwnedRacer != "" {
+			participant.Owned = &OwnedRacer{Name: p.OwnedRacer}
+		}
+		participants = append(participants, participant)
+	}
+
+	legs := make([]*RaceLeg, 0, len(record.Legs))
+	for _, positions := range record.Legs {
+		leg := &RaceLeg{
+			ParticipantPositions: make([]*RaceParticipantPosition, 0, len(positions)),
+		}
+		for idx, position := range positions {
+			if idx >= len(participants) {
+				break
+			}
+			leg.ParticipantPositions = append(leg.ParticipantPositions, &RaceParticipantPosition{
+				RaceParticipant: participants[idx],
+				Position:        position,
+				Finished:        position <= 0,
+			})
+		}
+		legs = append(legs, leg)
+	}
+
+	return legs
+}
@@ -0,0 +1,51 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestRaceRecordLegs(t *testing.T) {
+	record := &RaceRecord{
+		GuildID: "12345",
+		Participants: []*RaceRecordParticipant{
+			{MemberID: "1", Name: "first", Emoji: ":a:", Finish: 2},
+			{MemberID: "2", Name: "second", Emoji: ":b:", Finish: 1, OwnedRacer: "Speedy"},
+		},
+		Legs: [][]int{
+			{60, 60},
+			{40, 30},
+			{10, 0},
+		},
+	}
+
+	winner := record.Winner()
+	if winner == nil || winner.MemberID != "2" {
+		t.Errorf("expected member 2 to win, got %v", winner)
+	}
+
+	legs := record.getLegs()
+	if len(legs) != len(record.Legs) {
+		t.Fatalf("expected %d legs, got %d", len(record.Legs), len(legs))
+	}
+	for idx, leg := range legs {
+		if len(leg.ParticipantPositions) != len(record.Participants) {
+			t.Fatalf("expected %d positions in leg %d, got %d", len(record.Participants), idx, len(leg.ParticipantPositions))
+		}
+		for pos, position := range leg.ParticipantPositions {
+			if position.Position != record.Legs[idx][pos] {
+				t.Errorf("expected position %d in leg %d, got %d", record.Legs[idx][pos], idx, position.Position)
+			}
+		}
+	}
+
+	last := legs[len(legs)-1]
+	if last.ParticipantPositions[0].Finished {
+		t.Error("expected the first racer to still be running")
+	}
+	if !last.ParticipantPositions[1].Finished {
+		t.Error("expected the second racer to have finished")
+	}
+	if last.ParticipantPositions[1].RaceParticipant.getName() != "second (Speedy)" {
+		t.Errorf("expected the owned racer name, got %s", last.ParticipantPositions[1].RaceParticipant.getName())
+	}
+}
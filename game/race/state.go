@@ -0,0 +1,128 @@
+package race
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/language"
+)
+
+// Stages a race goes through. These are saved so that, if the bot is restarted, it
+// is known whether the betters still need to be refunded.
+const (
+	RACE_PLANNING = "planning"
+	RACE_BETTING  = "betting"
+	RACE_STARTED  = "started"
+	RACE_COMPLETE = "complete"
+)
+
+// RaceState is the saved state of a race that is being planned or is in progress. It is used
+// to clean up after a race if the bot is restarted before the race ends.
+type RaceState struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string             `json:"guild_id" bson:"guild_id"`
+	ChannelID string             `json:"channel_id" bson:"channel_id"`
+	RacerIDs  []string           `json:"racer_ids" bson:"racer_ids"`
+	Bets      []*RaceBetState    `json:"bets" bson:"bets"`
+	EntryFee  int                `json:"entry_fee" bson:"entry_fee"`
+	Stage     string             `json:"stage" bson:"stage"`
+}
+
+// RaceBetState is the saved state of a bet placed on a race.
+type RaceBetState struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	RacerID  string `json:"racer_id" bson:"racer_id"`
+	Amount   int    `json:"amount" bson:"amount"`
+}
+
+// saveState saves the current state of the race to the database. The caller is expected to
+// hold the race's lock.
+func (race *Race) saveState() {
+	log.Trace("--> race.Race.saveState")
+	defer log.Trace("<-- race.Race.saveState")
+
+	state := &RaceState{
+		GuildID:  race.GuildID,
+		RacerIDs: make([]string, 0, len(race.Racers)),
+		Bets:     make([]*RaceBetState, 0, len(race.Betters)),
+		EntryFee: race.config.EntryFee,
+		Stage:    race.stage,
+	}
+	if race.interaction != nil {
+		state.ChannelID = race.interaction.ChannelID
+	}
+	for _, racer := range race.Racers {
+		state.RacerIDs = append(state.RacerIDs, racer.Member.MemberID)
+	}
+	for _, better := range race.Betters {
+		bet := &RaceBetState{
+			MemberID: better.Member.MemberID,
+			RacerID:  better.Racer.Member.MemberID,
+			Amount:   better.Amount,
+		}
+		state.Bets = append(state.Bets, bet)
+	}
+
+	writeRaceState(state)
+}
+
+// recoverRaces cleans up any races that were being planned or were in progress when the bot
+// was last stopped. Any bets are refunded and the channel is told what happened.
+func recoverRaces(s *discordgo.Session) {
+	log.Trace("--> race.recoverRaces")
+	defer log.Trace("<-- race.recoverRaces")
+
+	states, err := readRaceStates()
+	if err != nil {
+		log.WithField("error", err).Error("unable to read the saved race states")
+		return
+	}
+
+	for _, state := range states {
+		recoverRace(s, state)
+	}
+}
+
+// recoverRace cleans up a single race that was interrupted by the bot being restarted.
+func recoverRace(s *discordgo.Session, state *RaceState) {
+	log.Trace("--> race.recoverRace")
+	defer log.Trace("<-- race.recoverRace")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	refunded := state.Stage != RACE_COMPLETE
+	if refunded {
+		if state.EntryFee > 0 {
+			for _, racerID := range state.RacerIDs {
+				account := bank.GetAccount(state.GuildID, racerID)
+				account.DepositWithReason(state.EntryFee, "race entry fee refund")
+			}
+		}
+		for _, bet := range state.Bets {
+			account := bank.GetAccount(state.GuildID, bet.MemberID)
+			account.DepositWithReason(bet.Amount, "race bet refund")
+		}
+		log.WithFields(log.Fields{"guild": state.GuildID, "racers": len(state.RacerIDs), "bets": len(state.Bets)}).Info("refunded bets for interrupted race")
+	}
+
+	if state.ChannelID != "" {
+		var msg string
+		if refunded {
+			msg = p.Sprintf("The bot was restarted while a race was in progress. The race with %d racers has been cancelled and all entry fees and %d bets have been refunded.",
+				len(state.RacerIDs),
+				len(state.Bets),
+			)
+		} else {
+			msg = p.Sprintf("The bot was restarted while the results of the race were being distributed. The race has been ended.")
+		}
+		_, err := s.ChannelMessageSend(state.ChannelID, msg)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": state.GuildID, "error": err}).Warn("unable to report the interrupted race to the channel")
+		}
+	}
+
+	deleteRaceState(state.GuildID)
+	log.WithFields(log.Fields{"guild": state.GuildID, "stage": state.Stage}).Info("recovered interrupted race")
+}
@@ -0,0 +1,80 @@
+package race
+
+import (
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DEFAULT_THEME = "clash"
+)
+
+// Theme is a named set of racers that may be used in a race.
+type Theme struct {
+	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID string             `json:"guild_id" bson:"guild_id"`
+	Name    string             `json:"name" bson:"name"`
+}
+
+// GetThemeNames returns the names of the race themes available to the guild. The default
+// theme is always available.
+func GetThemeNames(guildID string) []string {
+	log.Trace("--> race.GetThemeNames")
+	defer log.Trace("<-- race.GetThemeNames")
+
+	names := []string{DEFAULT_THEME}
+	themes, err := readThemes(guildID)
+	if err != nil {
+		return names
+	}
+	for _, theme := range themes {
+		if !slices.Contains(names, theme.Name) {
+			names = append(names, theme.Name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// NewTheme creates a new race theme for the guild. The theme has no racers until they are added.
+func NewTheme(guildID string, name string) (*Theme, error) {
+	log.Trace("--> race.NewTheme")
+	defer log.Trace("<-- race.NewTheme")
+
+	if slices.Contains(GetThemeNames(guildID), name) {
+		return nil, ErrThemeExists
+	}
+
+	theme := &Theme{
+		GuildID: guildID,
+		Name:    name,
+	}
+	writeTheme(theme)
+	log.WithFields(log.Fields{"guild": guildID, "theme": name}).Info("created race theme")
+
+	return theme, nil
+}
+
+// SetTheme changes the theme used for races in the guild. The theme must exist and have at
+// least one racer.
+func SetTheme(guildID string, name string) error {
+	log.Trace("--> race.SetTheme")
+	defer log.Trace("<-- race.SetTheme")
+
+	if !slices.Contains(GetThemeNames(guildID), name) {
+		return ErrThemeNotFound
+	}
+	if len(GetRacers(guildID, name)) == 0 {
+		return ErrNoRacersFound
+	}
+
+	config := GetConfig(guildID)
+	config.Theme = name
+	writeConfig(config)
+	log.WithFields(log.Fields{"guild": guildID, "theme": name}).Info("set race theme")
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package race
+
+import (
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNewTheme(t *testing.T) {
+	defer db.DeleteMany(RACE_THEME_COLLECTION, bson.M{"guild_id": "123"})
+	defer db.DeleteMany(RACER_COLLECTION, bson.M{"guild_id": "123", "theme": "space"})
+
+	_, err := NewTheme("123", "space")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(GetThemeNames("123"), "space") {
+		t.Error("expected the new theme to be listed")
+	}
+	if _, err := NewTheme("123", "space"); err != ErrThemeExists {
+		t.Errorf("expected %v, got %v", ErrThemeExists, err)
+	}
+	if err := SetTheme("123", "space"); err != ErrNoRacersFound {
+		t.Errorf("expected %v, got %v", ErrNoRacersFound, err)
+	}
+
+	if _, err := NewRacer("123", "space", "🚀", "fast"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRacer("123", "space", "🚀", "fast"); err != ErrRacerExists {
+		t.Errorf("expected %v, got %v", ErrRacerExists, err)
+	}
+	if _, err := NewRacer("123", "space", "🛸", "unknown"); err != ErrProfileNotFound {
+		t.Errorf("expected %v, got %v", ErrProfileNotFound, err)
+	}
+	if err := RemoveRacer("123", "space", "🛸"); err != ErrRacerNotFound {
+		t.Errorf("expected %v, got %v", ErrRacerNotFound, err)
+	}
+	if err := RemoveRacer("123", "space", "🚀"); err != nil {
+		t.Error(err)
+	}
+}
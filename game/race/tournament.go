@@ -0,0 +1,538 @@
+package race
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	"github.com/rbrabson/goblin/internal/format"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/language"
+)
+
+// Stages a tournament goes through.
+const (
+	TOURNAMENT_REGISTRATION = "registration"
+	TOURNAMENT_RUNNING      = "running"
+	TOURNAMENT_COMPLETE     = "complete"
+	TOURNAMENT_CANCELLED    = "cancelled"
+)
+
+const (
+	TOURNAMENT_HEAT_SIZE   = 6                // Default number of racers in each heat
+	TOURNAMENT_ADVANCE     = 2                // Default number of racers in each heat who advance to the next round
+	TOURNAMENT_INTERVAL    = 10 * time.Minute // Default time between rounds of a tournament
+	MIN_TOURNAMENT_RACERS  = 2                // Minimum number of registered members needed to start a tournament
+	MIN_TOURNAMENT_HEAT    = 2                // Minimum size of a heat
+	TOURNAMENT_HEAT_PAUSE  = 5 * time.Second  // Time between heats within a round, so the results can be followed
+	TOURNAMENT_MAX_ENTRIES = 100
+)
+
+var (
+	// tournamentLock serializes changes to the tournaments, which may be updated by both
+	// commands and the scheduler that runs the rounds.
+	tournamentLock = sync.Mutex{}
+	// scheduledTournaments are the guilds with a goroutine running the rounds of a tournament.
+	scheduledTournaments = make(map[string]bool)
+)
+
+// Tournament is a series of races in which members race in heats, and the top finishers of
+// each heat advance to the next round, until a champion is crowned in the final heat.
+type Tournament struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID    string             `json:"guild_id" bson:"guild_id"`
+	ChannelID  string             `json:"channel_id" bson:"channel_id"`
+	Name       string             `json:"name" bson:"name"`
+	Season     int                `json:"season" bson:"season"`
+	Stage      string             `json:"stage" bson:"stage"`
+	EntrantIDs []string           `json:"entrant_ids" bson:"entrant_ids"`
+	Rounds     []*TournamentRound `json:"rounds" bson:"rounds"`
+	HeatSize   int                `json:"heat_size" bson:"heat_size"`
+	Advance    int                `json:"advance" bson:"advance"`
+	Interval   time.Duration      `json:"interval" bson:"interval"`
+	NextRound  time.Time          `json:"next_round" bson:"next_round"`
+	EntryFee   int                `json:"entry_fee" bson:"entry_fee"`
+	PrizePool  int                `json:"prize_pool" bson:"prize_pool"`
+	RoleID     string             `json:"role_id,omitempty" bson:"role_id,omitempty"`
+	ChampionID string             `json:"champion_id,omitempty" bson:"champion_id,omitempty"`
+	Created    time.Time          `json:"created" bson:"created"`
+}
+
+// TournamentRound is a single round of a tournament, made up of one or more heats.
+type TournamentRound struct {
+	Number int               `json:"number" bson:"number"`
+	Heats  []*TournamentHeat `json:"heats" bson:"heats"`
+}
+
+// TournamentHeat is a single race within a round of a tournament.
+type TournamentHeat struct {
+	Number    int      `json:"number" bson:"number"`
+	MemberIDs []string `json:"member_ids" bson:"member_ids"`
+	Finishers []string `json:"finishers" bson:"finishers"`
+	Advancing []string `json:"advancing" bson:"advancing"`
+}
+
+// TournamentOptions are the settings used when opening a tournament.
+type TournamentOptions struct {
+	Name     string
+	Prize    int
+	EntryFee int
+	HeatSize int
+	Advance  int
+	Interval time.Duration
+	RoleID   string
+}
+
+// GetTournament returns the tournament that is open for registration or running in the guild,
+// or `nil` if there isn't one.
+func GetTournament(guildID string) *Tournament {
+	log.Trace("--> race.GetTournament")
+	defer log.Trace("<-- race.GetTournament")
+
+	return readActiveTournament(guildID)
+}
+
+// OpenTournament opens registration for a new tournament in the guild.
+func OpenTournament(guildID string, channelID string, options *TournamentOptions) (*Tournament, error) {
+	log.Trace("--> race.OpenTournament")
+	defer log.Trace("<-- race.OpenTournament")
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	if readActiveTournament(guildID) != nil {
+		return nil, ErrTournamentInProgress
+	}
+	if options.HeatSize < MIN_TOURNAMENT_HEAT || options.HeatSize > MAX_BET_BUTTONS {
+		return nil, ErrInvalidTournament{"the heat size must be between 2 and 11 racers"}
+	}
+	if options.Advance < 1 || options.Advance >= options.HeatSize {
+		return nil, ErrInvalidTournament{"the number of racers who advance must be at least 1 and less than the heat size"}
+	}
+	if options.Prize < 0 || options.EntryFee < 0 {
+		return nil, ErrInvalidTournament{"the prize and entry fee may not be negative"}
+	}
+
+	tournament := &Tournament{
+		GuildID:    guildID,
+		ChannelID:  channelID,
+		Name:       options.Name,
+		Season:     countTournaments(guildID) + 1,
+		Stage:      TOURNAMENT_REGISTRATION,
+		EntrantIDs: make([]string, 0, options.HeatSize*2),
+		Rounds:     make([]*TournamentRound, 0, 3),
+		HeatSize:   options.HeatSize,
+		Advance:    options.Advance,
+		Interval:   options.Interval,
+		EntryFee:   options.EntryFee,
+		PrizePool:  options.Prize,
+		RoleID:     options.RoleID,
+		Created:    time.Now(),
+	}
+	writeTournament(tournament)
+	log.WithFields(log.Fields{"guild": guildID, "tournament": tournament.Name, "season": tournament.Season}).Info("opened tournament")
+
+	return tournament, nil
+}
+
+// Register adds the member to the tournament, charging them the entry fee. The entry fee is
+// added to the prize pool.
+func (t *Tournament) Register(memberID string) error {
+	log.Trace("--> race.Tournament.Register")
+	defer log.Trace("<-- race.Tournament.Register")
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+	t.refresh()
+
+	if t.Stage != TOURNAMENT_REGISTRATION {
+		return ErrRegistrationClosed
+	}
+	if slices.Contains(t.EntrantIDs, memberID) {
+		return ErrAlreadyRegistered
+	}
+	if len(t.EntrantIDs) >= TOURNAMENT_MAX_ENTRIES {
+		return ErrTournamentFull
+	}
+	if t.EntryFee > 0 {
+		account := bank.GetAccount(t.GuildID, memberID)
+		if err := account.WithdrawWithReason(t.EntryFee, "tournament entry fee"); err != nil {
+			return ErrNotEnoughCredits{CreditsNeeded: t.EntryFee}
+		}
+	}
+
+	t.EntrantIDs = append(t.EntrantIDs, memberID)
+	t.PrizePool += t.EntryFee
+	writeTournament(t)
+	log.WithFields(log.Fields{"guild": t.GuildID, "tournament": t.Name, "member": memberID}).Info("registered for tournament")
+
+	return nil
+}
+
+// Start closes registration for the tournament, seeds the first round, and schedules the
+// rounds to be run.
+func (t *Tournament) Start(s *discordgo.Session) error {
+	log.Trace("--> race.Tournament.Start")
+	defer log.Trace("<-- race.Tournament.Start")
+
+	tournamentLock.Lock()
+	t.refresh()
+	if t.Stage != TOURNAMENT_REGISTRATION {
+		tournamentLock.Unlock()
+		return ErrRegistrationClosed
+	}
+	if len(t.EntrantIDs) < MIN_TOURNAMENT_RACERS {
+		tournamentLock.Unlock()
+		return ErrNotEnoughEntrants
+	}
+
+	t.Rounds = append(t.Rounds, seedRound(1, t.EntrantIDs, t.HeatSize))
+	t.Stage = TOURNAMENT_RUNNING
+	t.NextRound = time.Now()
+	writeTournament(t)
+	tournamentLock.Unlock()
+	log.WithFields(log.Fields{"guild": t.GuildID, "tournament": t.Name, "entrants": len(t.EntrantIDs)}).Info("started tournament")
+
+	scheduleTournament(s, t.GuildID)
+
+	return nil
+}
+
+// Cancel cancels the tournament. Any entry fees are refunded.
+func (t *Tournament) Cancel() error {
+	log.Trace("--> race.Tournament.Cancel")
+	defer log.Trace("<-- race.Tournament.Cancel")
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+	t.refresh()
+
+	if t.Stage != TOURNAMENT_REGISTRATION && t.Stage != TOURNAMENT_RUNNING {
+		return ErrNoTournament
+	}
+	if t.EntryFee > 0 {
+		for _, memberID := range t.EntrantIDs {
+			account := bank.GetAccount(t.GuildID, memberID)
+			account.DepositWithReason(t.EntryFee, "tournament entry fee refund")
+		}
+	}
+
+	t.Stage = TOURNAMENT_CANCELLED
+	writeTournament(t)
+	log.WithFields(log.Fields{"guild": t.GuildID, "tournament": t.Name}).Info("cancelled tournament")
+
+	return nil
+}
+
+// refresh reloads the tournament from the database, so that changes made by other commands
+// aren't lost. The caller is expected to hold the tournament lock.
+func (t *Tournament) refresh() {
+	current := readActiveTournament(t.GuildID)
+	if current != nil && current.ID == t.ID {
+		*t = *current
+	}
+}
+
+// currentRound returns the round of the tournament that is to be run next.
+func (t *Tournament) currentRound() *TournamentRound {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return t.Rounds[len(t.Rounds)-1]
+}
+
+// seedRound creates a round of a tournament, splitting the members into heats of no more
+// than the heat size. The members are shuffled, and the heats are kept as even in size as
+// possible.
+func seedRound(number int, memberIDs []string, heatSize int) *TournamentRound {
+	log.Trace("--> race.seedRound")
+	defer log.Trace("<-- race.seedRound")
+
+	seeds := slices.Clone(memberIDs)
+	rand.Shuffle(len(seeds), func(i, j int) {
+		seeds[i], seeds[j] = seeds[j], seeds[i]
+	})
+
+	numHeats := (len(seeds) + heatSize - 1) / heatSize
+	round := &TournamentRound{
+		Number: number,
+		Heats:  make([]*TournamentHeat, 0, numHeats),
+	}
+	for idx := range numHeats {
+		round.Heats = append(round.Heats, &TournamentHeat{
+			Number:    idx + 1,
+			MemberIDs: make([]string, 0, heatSize),
+		})
+	}
+	for idx, memberID := range seeds {
+		heat := round.Heats[idx%numHeats]
+		heat.MemberIDs = append(heat.MemberIDs, memberID)
+	}
+
+	return round
+}
+
+// runRound runs each heat in the current round of the tournament. The top finishers in each
+// heat advance to the next round. If this was the final heat, the champion is crowned.
+func (t *Tournament) runRound(s *discordgo.Session) {
+	log.Trace("--> race.Tournament.runRound")
+	defer log.Trace("<-- race.Tournament.runRound")
+
+	round := t.currentRound()
+	final := len(round.Heats) == 1
+	config := GetConfig(t.GuildID)
+
+	advancing := make([]string, 0, len(round.Heats)*t.Advance)
+	for _, heat := range round.Heats {
+		race := runHeat(t.GuildID, config, heat.MemberIDs)
+		heat.Finishers = make([]string, 0, len(race.RaceResult.Finishers))
+		for _, finisher := range race.RaceResult.Finishers {
+			heat.Finishers = append(heat.Finishers, finisher.Member.MemberID)
+		}
+		numAdvancing := min(t.Advance, max(len(heat.Finishers)-1, 1))
+		heat.Advancing = slices.Clone(heat.Finishers[:numAdvancing])
+		advancing = append(advancing, heat.Advancing...)
+
+		sendHeatResults(s, t, round, heat, race)
+		time.Sleep(TOURNAMENT_HEAT_PAUSE)
+	}
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	// The tournament may have been cancelled while the heats were being run.
+	if current := readActiveTournament(t.GuildID); current == nil || current.Stage != TOURNAMENT_RUNNING {
+		log.WithFields(log.Fields{"guild": t.GuildID, "tournament": t.Name}).Info("tournament cancelled during round")
+		return
+	}
+
+	if final {
+		t.ChampionID = round.Heats[0].Finishers[0]
+		t.Stage = TOURNAMENT_COMPLETE
+		writeTournament(t)
+		t.crownChampion(s)
+		return
+	}
+
+	t.Rounds = append(t.Rounds, seedRound(round.Number+1, advancing, t.HeatSize))
+	t.NextRound = time.Now().Add(t.Interval)
+	writeTournament(t)
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	msg := p.Sprintf("Round %d of the %s tournament is complete. %d racers advance to round %d, which starts in %s.",
+		round.Number,
+		t.Name,
+		len(advancing),
+		round.Number+1,
+		format.Duration(t.Interval),
+	)
+	sendTournamentMessage(s, t, msg)
+}
+
+// runHeat runs a race between the members in a heat of a tournament. Members race with their own
+// racer if they have one, regardless of how tired it is, and otherwise are assigned a racer.
+func runHeat(guildID string, config *Config, memberIDs []string) *Race {
+	log.Trace("--> race.runHeat")
+	defer log.Trace("<-- race.runHeat")
+
+	race := &Race{
+		GuildID:   guildID,
+		StartTime: time.Now(),
+		Racers:    make([]*RaceParticipant, 0, len(memberIDs)),
+		config:    config,
+		stage:     RACE_STARTED,
+		mutex:     sync.Mutex{},
+	}
+
+	racers := GetRacers(guildID, config.Theme)
+	profiles := GetMovementProfiles(guildID, config.Theme)
+	for _, memberID := range memberIDs {
+		member := GetRaceMember(guildID, memberID)
+		var participant *RaceParticipant
+		owned := GetOwnedRacer(guildID, memberID)
+		switch {
+		case owned != nil:
+			participant = newOwnedRaceParticipant(member, owned, profiles)
+		case len(racers) > 0:
+			participant = newRaceParitipcant(member, race.availableRacers(racers))
+		default:
+			participant = newRaceParitipcant(member, []*Racer{{GuildID: guildID, Emoji: "🏇", MovementSpeed: DEFAULT_MOVEMENT_PROFILE}})
+		}
+		race.Racers = append(race.Racers, participant)
+	}
+	race.RunRace(TRACK_LENGTH)
+
+	return race
+}
+
+// crownChampion pays the prize pool to the champion of the tournament and, if configured, gives
+// them the champion's role. The caller is expected to hold the tournament lock.
+func (t *Tournament) crownChampion(s *discordgo.Session) {
+	log.Trace("--> race.Tournament.crownChampion")
+	defer log.Trace("<-- race.Tournament.crownChampion")
+
+	if t.PrizePool > 0 {
+		account := bank.GetAccount(t.GuildID, t.ChampionID)
+		account.DepositWithReason(t.PrizePool, "tournament prize")
+	}
+	if t.RoleID != "" {
+		err := s.GuildMemberRoleAdd(t.GuildID, t.ChampionID, t.RoleID)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": t.GuildID, "member": t.ChampionID, "role": t.RoleID, "error": err}).Error("unable to give the champion their role")
+		}
+	}
+	log.WithFields(log.Fields{"guild": t.GuildID, "tournament": t.Name, "champion": t.ChampionID, "prize": t.PrizePool}).Info("crowned tournament champion")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	msg := p.Sprintf("🏆 <@%s> is the champion of the %s tournament and wins %d credits!", t.ChampionID, t.Name, t.PrizePool)
+	if t.RoleID != "" {
+		msg += p.Sprintf(" They have been given the <@&%s> role.", t.RoleID)
+	}
+	sendTournamentMessage(s, t, msg)
+}
+
+// sendHeatResults sends the final positions and finishing order of a heat to the tournament's channel.
+func sendHeatResults(s *discordgo.Session, t *Tournament, round *TournamentRound, heat *TournamentHeat, race *Race) {
+	log.Trace("--> race.sendHeatResults")
+	defer log.Trace("<-- race.sendHeatResults")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	var sb strings.Builder
+	if len(race.RaceLegs) > 0 {
+		sb.WriteString(renderTrack(race.RaceLegs[len(race.RaceLegs)-1], race.config, TRACK_LENGTH))
+		sb.WriteString("\n")
+	}
+	for idx, finisher := range race.RaceResult.Finishers {
+		marker := ""
+		if slices.Contains(heat.Advancing, finisher.Member.MemberID) {
+			marker = " ✅"
+		}
+		sb.WriteString(p.Sprintf("%d. %s %s%s\n", idx+1, finisher.Racer.Emoji, finisher.getName(), marker))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Type:        discordgo.EmbedTypeRich,
+		Title:       p.Sprintf("%s: Round %d, Heat %d", t.Name, round.Number, heat.Number),
+		Description: sb.String(),
+	}
+	_, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": t.GuildID, "error": err}).Error("unable to send the heat results")
+	}
+}
+
+// sendTournamentMessage sends a message to the tournament's channel.
+func sendTournamentMessage(s *discordgo.Session, t *Tournament, msg string) {
+	_, err := s.ChannelMessageSend(t.ChannelID, msg)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": t.GuildID, "error": err}).Error("unable to send the tournament message")
+	}
+}
+
+// scheduleTournament starts a goroutine that runs the rounds of the guild's tournament as they
+// come due. Only one goroutine is run for each guild.
+func scheduleTournament(s *discordgo.Session, guildID string) {
+	log.Trace("--> race.scheduleTournament")
+	defer log.Trace("<-- race.scheduleTournament")
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	if scheduledTournaments[guildID] {
+		return
+	}
+	scheduledTournaments[guildID] = true
+	go runTournament(s, guildID)
+}
+
+// runTournament runs each round of the guild's tournament when it is due, until the tournament
+// is complete or cancelled.
+func runTournament(s *discordgo.Session, guildID string) {
+	log.Trace("--> race.runTournament")
+	defer log.Trace("<-- race.runTournament")
+
+	defer func() {
+		tournamentLock.Lock()
+		delete(scheduledTournaments, guildID)
+		tournamentLock.Unlock()
+	}()
+
+	for {
+		// The tournament is re-read each time, as it may have been cancelled while waiting.
+		tournament := GetTournament(guildID)
+		if tournament == nil || tournament.Stage != TOURNAMENT_RUNNING {
+			return
+		}
+		if wait := time.Until(tournament.NextRound); wait > 0 {
+			time.Sleep(min(wait, time.Minute))
+			continue
+		}
+		if !guild.IsPluginEnabled(guildID, PLUGIN_NAME) {
+			// Hold the tournament until races are enabled again
+			time.Sleep(time.Minute)
+			continue
+		}
+		tournament.runRound(s)
+	}
+}
+
+// resumeTournaments restarts the scheduler for any tournaments that were running when the bot
+// was last stopped.
+func resumeTournaments(s *discordgo.Session) {
+	log.Trace("--> race.resumeTournaments")
+	defer log.Trace("<-- race.resumeTournaments")
+
+	tournaments, err := readRunningTournaments()
+	if err != nil {
+		log.WithField("error", err).Error("unable to read the running tournaments")
+		return
+	}
+	for _, tournament := range tournaments {
+		scheduleTournament(s, tournament.GuildID)
+		log.WithFields(log.Fields{"guild": tournament.GuildID, "tournament": tournament.Name}).Info("resumed tournament")
+	}
+}
+
+// renderBracket returns the embed fields showing each round of the tournament. Members who
+// advanced from a heat are shown in bold.
+func renderBracket(t *Tournament) []*discordgo.MessageEmbedField {
+	fields := make([]*discordgo.MessageEmbedField, 0, len(t.Rounds))
+	for _, round := range t.Rounds {
+		var sb strings.Builder
+		for _, heat := range round.Heats {
+			names := make([]string, 0, len(heat.MemberIDs))
+			memberIDs := heat.MemberIDs
+			if len(heat.Finishers) > 0 {
+				memberIDs = heat.Finishers
+			}
+			for _, memberID := range memberIDs {
+				name := GetRaceMember(t.GuildID, memberID).getName()
+				if slices.Contains(heat.Advancing, memberID) {
+					name = "**" + name + "**"
+				}
+				names = append(names, name)
+			}
+			sb.WriteString(fmt.Sprintf("Heat %d: %s\n", heat.Number, strings.Join(names, ", ")))
+		}
+		name := fmt.Sprintf("Round %d", round.Number)
+		if len(round.Heats) == 1 {
+			name = "Final"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: sb.String(),
+		})
+	}
+	return fields
+}
@@ -0,0 +1,42 @@
+package race
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSeedRound(t *testing.T) {
+	memberIDs := make([]string, 0, 13)
+	for i := range 13 {
+		memberIDs = append(memberIDs, fmt.Sprintf("%d", i))
+	}
+
+	round := seedRound(1, memberIDs, 6)
+	if len(round.Heats) != 3 {
+		t.Fatalf("expected 3 heats, got %d", len(round.Heats))
+	}
+	seeded := make([]string, 0, len(memberIDs))
+	for _, heat := range round.Heats {
+		if len(heat.MemberIDs) < 4 || len(heat.MemberIDs) > 5 {
+			t.Errorf("expected heats of 4 or 5 racers, got %d", len(heat.MemberIDs))
+		}
+		seeded = append(seeded, heat.MemberIDs...)
+	}
+	slices.Sort(seeded)
+	sorted := slices.Clone(memberIDs)
+	slices.Sort(sorted)
+	if !slices.Equal(seeded, sorted) {
+		t.Errorf("expected every member to be seeded once, got %v", seeded)
+	}
+}
+
+func TestSeedFinal(t *testing.T) {
+	round := seedRound(3, []string{"1", "2", "3"}, 6)
+	if len(round.Heats) != 1 {
+		t.Errorf("expected a single final heat, got %d", len(round.Heats))
+	}
+	if round.Number != 3 {
+		t.Errorf("expected round 3, got %d", round.Number)
+	}
+}
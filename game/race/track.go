@@ -0,0 +1,89 @@
+package race
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TRACK_SEGMENT      = "•"
+	TRACK_SEGMENTS     = 20
+	ANIMATION_INTERVAL = 2 * time.Second // Time between updates, which keeps the edits well under Discord's rate limit
+	MAX_FRAMES         = 30              // Maximum number of legs shown when animating a race
+)
+
+// renderTrack returns the text showing the position of each racer on the track for a leg of
+// the race. Each racer is shown on their own lane, which starts with the starting line and ends
+// with the ending line of the track.
+func renderTrack(leg *RaceLeg, config *Config, trackLength int) string {
+	log.Trace("--> race.renderTrack")
+	defer log.Trace("<-- race.renderTrack")
+
+	var sb strings.Builder
+	for _, position := range leg.ParticipantPositions {
+		segment := min(max(position.Position, 0), trackLength) * TRACK_SEGMENTS / trackLength
+		lane := strings.Repeat(TRACK_SEGMENT, segment) + position.RaceParticipant.Racer.Emoji + strings.Repeat(TRACK_SEGMENT, TRACK_SEGMENTS-segment)
+		sb.WriteString(fmt.Sprintf("%s%s%s **%s**\n",
+			config.StartingLine,
+			lane,
+			config.EndingLine,
+			position.RaceParticipant.getName(),
+		))
+	}
+
+	return sb.String()
+}
+
+// getFrames returns the legs of the race that are shown when animating the race. If the race
+// has more legs than can be shown, legs are skipped, though the first and last legs are
+// always included.
+func getFrames(legs []*RaceLeg) []*RaceLeg {
+	if len(legs) <= MAX_FRAMES {
+		return legs
+	}
+
+	frames := make([]*RaceLeg, 0, MAX_FRAMES)
+	step := float64(len(legs)-1) / float64(MAX_FRAMES-1)
+	for i := 0; i < MAX_FRAMES; i++ {
+		frames = append(frames, legs[int(float64(i)*step+0.5)])
+	}
+
+	return frames
+}
+
+// animateRace shows the race in the channel, leg by leg, by repeatedly editing a single message.
+func animateRace(s *discordgo.Session, race *Race, trackLength int) {
+	log.Trace("--> race.animateRace")
+	defer log.Trace("<-- race.animateRace")
+
+	animateLegs(s, race.GuildID, race.interaction.ChannelID, race.RaceLegs, race.config, trackLength)
+}
+
+// animateLegs shows the legs of a race in the channel by repeatedly editing a single message.
+func animateLegs(s *discordgo.Session, guildID string, channelID string, legs []*RaceLeg, config *Config, trackLength int) {
+	log.Trace("--> race.animateLegs")
+	defer log.Trace("<-- race.animateLegs")
+
+	frames := getFrames(legs)
+	if len(frames) == 0 {
+		return
+	}
+
+	msg, err := s.ChannelMessageSend(channelID, renderTrack(frames[0], config, trackLength))
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to send the race track")
+		return
+	}
+
+	for _, frame := range frames[1:] {
+		time.Sleep(ANIMATION_INTERVAL)
+		_, err := s.ChannelMessageEdit(channelID, msg.ID, renderTrack(frame, config, trackLength))
+		if err != nil {
+			log.WithFields(log.Fields{"guild": guildID, "error": err}).Warn("unable to update the race track")
+		}
+	}
+}
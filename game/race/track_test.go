@@ -0,0 +1,47 @@
+package race
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbrabson/goblin/guild"
+)
+
+func TestRenderTrack(t *testing.T) {
+	config := &Config{StartingLine: "🏁", EndingLine: "|"}
+	participant := &RaceParticipant{
+		Member: &RaceMember{GuildID: "123", MemberID: "456", guildMember: &guild.Member{Name: "Racer"}},
+		Racer:  &Racer{Emoji: "X"},
+	}
+	leg := &RaceLeg{
+		ParticipantPositions: []*RaceParticipantPosition{
+			{RaceParticipant: participant, Position: TRACK_LENGTH / 2},
+		},
+	}
+
+	track := renderTrack(leg, config, TRACK_LENGTH)
+	expected := "🏁" + strings.Repeat(TRACK_SEGMENT, TRACK_SEGMENTS/2) + "X" + strings.Repeat(TRACK_SEGMENT, TRACK_SEGMENTS/2) + "| **Racer**\n"
+	if track != expected {
+		t.Errorf("expected %q, got %q", expected, track)
+	}
+}
+
+func TestGetFrames(t *testing.T) {
+	legs := make([]*RaceLeg, 0, MAX_FRAMES*2)
+	for range MAX_FRAMES * 2 {
+		legs = append(legs, &RaceLeg{})
+	}
+
+	frames := getFrames(legs)
+	if len(frames) != MAX_FRAMES {
+		t.Errorf("expected %d frames, got %d", MAX_FRAMES, len(frames))
+	}
+	if frames[0] != legs[0] || frames[len(frames)-1] != legs[len(legs)-1] {
+		t.Error("expected the first and last legs to be included")
+	}
+
+	frames = getFrames(legs[:5])
+	if len(frames) != 5 {
+		t.Errorf("expected 5 frames, got %d", len(frames))
+	}
+}
@@ -0,0 +1,119 @@
+package guild
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// CommandOverride allows or denies members with a role the use of a command, subcommand group or
+// subcommand, regardless of the permission the command normally requires.
+type CommandOverride struct {
+	Command string `json:"command" bson:"command"` // Full path of the command, such as "heist-admin clear"
+	RoleID  string `json:"role_id" bson:"role_id"`
+	Allow   bool   `json:"allow" bson:"allow"`
+}
+
+// SetCommandOverride allows or denies members with the role the use of the command.
+func (guild *Guild) SetCommandOverride(command string, roleID string, allow bool) {
+	log.Trace("--> guild.Guild.SetCommandOverride")
+	defer log.Trace("<-- guild.Guild.SetCommandOverride")
+
+	command = NormalizeCommand(command)
+	override := guild.getCommandOverride(command, roleID)
+	if override == nil {
+		override = &CommandOverride{Command: command, RoleID: roleID}
+		guild.CommandOverrides = append(guild.CommandOverrides, override)
+	}
+	override.Allow = allow
+	writeGuild(guild)
+	log.WithFields(log.Fields{"guild": guild.GuildID, "command": command, "role": roleID, "allow": allow}).Info("set command override")
+}
+
+// RemoveCommandOverride removes the override for the command and role, restoring the permission
+// the command normally requires.
+func (guild *Guild) RemoveCommandOverride(command string, roleID string) error {
+	log.Trace("--> guild.Guild.RemoveCommandOverride")
+	defer log.Trace("<-- guild.Guild.RemoveCommandOverride")
+
+	command = NormalizeCommand(command)
+	idx := slices.IndexFunc(guild.CommandOverrides, func(override *CommandOverride) bool {
+		return override.Command == command && override.RoleID == roleID
+	})
+	if idx == -1 {
+		return ErrCommandOverrideNotFound
+	}
+	guild.CommandOverrides = slices.Delete(guild.CommandOverrides, idx, idx+1)
+	writeGuild(guild)
+	log.WithFields(log.Fields{"guild": guild.GuildID, "command": command, "role": roleID}).Info("removed command override")
+
+	return nil
+}
+
+// getCommandOverride returns the override for the command and role, or `nil` if there isn't one.
+func (guild *Guild) getCommandOverride(command string, roleID string) *CommandOverride {
+	for _, override := range guild.CommandOverrides {
+		if override.Command == command && override.RoleID == roleID {
+			return override
+		}
+	}
+	return nil
+}
+
+// checkCommandOverrides returns whether the overrides for the most specific part of the command that has
+// an override for any of the member's roles allow the member to use the command. A deny takes precedence
+// over an allow at the same level. The second value is `false` if no override applies to the member.
+func (guild *Guild) checkCommandOverrides(command string, memberRoleIDs []string) (bool, bool) {
+	parts := strings.Fields(NormalizeCommand(command))
+	for n := len(parts); n > 0; n-- {
+		path := strings.Join(parts[:n], " ")
+		found, allowed := false, true
+		for _, override := range guild.CommandOverrides {
+			if override.Command != path || !slices.Contains(memberRoleIDs, override.RoleID) {
+				continue
+			}
+			found = true
+			allowed = allowed && override.Allow
+		}
+		if found {
+			return allowed, true
+		}
+	}
+	return false, false
+}
+
+// CanUseCommand returns whether the member may use the command in the guild. The owner of the guild may
+// always use every command. Otherwise, the overrides for the member's roles are checked, from the most to
+// the least specific part of the command, and if none apply the member must have the permission that the
+// command normally requires.
+func CanUseCommand(s *discordgo.Session, guildID string, memberID string, command string, permission Permission) bool {
+	log.Trace("--> guild.CanUseCommand")
+	defer log.Trace("<-- guild.CanUseCommand")
+
+	memberPermission := GetPermission(s, guildID, memberID)
+	if memberPermission == PermissionOwner {
+		return true
+	}
+
+	guild := GetGuild(guildID)
+	if len(guild.CommandOverrides) != 0 {
+		member, err := s.GuildMember(guildID, memberID)
+		if err != nil {
+			log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "error": err}).Error("failed to get guild member")
+			return false
+		}
+		if allowed, ok := guild.checkCommandOverrides(command, member.Roles); ok {
+			log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "command": command, "allowed": allowed}).Debug("command override")
+			return allowed
+		}
+	}
+
+	return memberPermission >= permission
+}
+
+// NormalizeCommand removes the leading slash and any extra whitespace from the command.
+func NormalizeCommand(command string) string {
+	return strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(command), "/")), " ")
+}
@@ -0,0 +1,41 @@
+package guild
+
+import "testing"
+
+func TestCheckCommandOverrides(t *testing.T) {
+	guild := &Guild{
+		GuildID: GUILD_ID,
+		CommandOverrides: []*CommandOverride{
+			{Command: "heist-admin", RoleID: "mod", Allow: false},
+			{Command: "heist-admin clear", RoleID: "mod", Allow: true},
+			{Command: "bank-admin", RoleID: "mod", Allow: true},
+			{Command: "bank-admin account", RoleID: "mod", Allow: false},
+			{Command: "bank-admin account", RoleID: "trusted", Allow: true},
+			{Command: "heist", RoleID: "jailed", Allow: false},
+		},
+	}
+
+	tests := []struct {
+		command string
+		roles   []string
+		allowed bool
+		found   bool
+	}{
+		{"heist-admin clear", []string{"mod"}, true, true},
+		{"/heist-admin  clear ", []string{"mod"}, true, true},
+		{"heist-admin reset", []string{"mod"}, false, true},
+		{"bank-admin balance", []string{"mod"}, true, true},
+		{"bank-admin account", []string{"mod"}, false, true},
+		{"bank-admin account", []string{"trusted"}, true, true},
+		{"bank-admin account", []string{"mod", "trusted"}, false, true}, // A deny wins at the same level
+		{"heist start", []string{"jailed"}, false, true},
+		{"heist start", []string{"mod"}, false, false},
+		{"race start", []string{"mod", "jailed"}, false, false},
+	}
+	for _, tc := range tests {
+		allowed, found := guild.checkCommandOverrides(tc.command, tc.roles)
+		if allowed != tc.allowed || found != tc.found {
+			t.Errorf("%q with roles %v: expected (%v, %v), got (%v, %v)", tc.command, tc.roles, tc.allowed, tc.found, allowed, found)
+		}
+	}
+}
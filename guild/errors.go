@@ -3,5 +3,11 @@ package guild
 import "errors"
 
 var (
+	ErrCommandOverrideNotFound = errors.New("no override is set for that command and role")
+	ErrInvalidPermission       = errors.New("invalid permission")
+	ErrPluginAlreadyDisabled   = errors.New("plugin is already disabled")
+	ErrPluginAlreadyEnabled    = errors.New("plugin is already enabled")
+	ErrInvalidRolePermission   = errors.New("roles can only be given the admin or moderator permission")
+	ErrRoleNotFound            = errors.New("role not found")
 	ErrUnableToSaveGuildMember = errors.New("unable to save guild member")
 )
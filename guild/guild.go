@@ -8,15 +8,15 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var (
-	DEFAULT_ADMIN_ROLES = []string{"Admin", "Admins", "Administrator", "Mod", "Mods", "Moderator"}
-)
-
 // Guild is the configuration for a guild (guild).
 type Guild struct {
-	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID    string             `json:"guild_id" bson:"guild_id"`
-	AdminRoles []string           `json:"admin_roles" bson:"admin_roles"`
+	ID               primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID          string             `json:"guild_id" bson:"guild_id"`
+	AdminRoles       []string           `json:"admin_roles" bson:"admin_roles"` // Role names stored by earlier versions; migrated to role IDs when first used
+	AdminRoleIDs     []string           `json:"admin_role_ids" bson:"admin_role_ids"`
+	ModeratorRoleIDs []string           `json:"moderator_role_ids" bson:"moderator_role_ids"`
+	CommandOverrides []*CommandOverride `json:"command_overrides" bson:"command_overrides"`
+	DisabledPlugins  []string           `json:"disabled_plugins" bson:"disabled_plugins"`
 }
 
 // GetGuild returns the guild configuration for a given guild (guild).
@@ -30,58 +30,106 @@ func GetGuild(guildID string) *Guild {
 	return guild
 }
 
-// newGuild creates a new guild configuration for a given guild (guild).
+// newGuild creates a new guild configuration for a given guild (guild). No roles are given permissions
+// until they are added; the owner of the guild and members with the Discord "Administrator" permission
+// are always admins.
 func newGuild(guildID string) *Guild {
 	guild := &Guild{
-		GuildID: guildID,
+		GuildID:          guildID,
+		AdminRoleIDs:     make([]string, 0, 1),
+		ModeratorRoleIDs: make([]string, 0, 1),
 	}
-	guild.AdminRoles = make([]string, len(DEFAULT_ADMIN_ROLES))
-	copy(guild.AdminRoles, DEFAULT_ADMIN_ROLES)
 	writeGuild(guild)
 
 	return guild
 }
 
-// AddAdminRole adds a role to the list of admin roles for the guild.
-func (guild *Guild) AddAdminRole(roleName string) {
-	log.Trace("--> guild.Guild.AddAdminRole")
-	defer log.Trace("<-- guild.Guild.AddAdminRole")
+// getRoleIDs returns a pointer to the list of role IDs for the permission, or `nil` if roles
+// can't be given the permission.
+func (guild *Guild) getRoleIDs(permission Permission) *[]string {
+	switch permission {
+	case PermissionAdmin:
+		return &guild.AdminRoleIDs
+	case PermissionModerator:
+		return &guild.ModeratorRoleIDs
+	default:
+		return nil
+	}
+}
+
+// AddRole gives the permission to members with the role. A role may only be assigned a single
+// permission, so any permission the role previously had is replaced.
+func (guild *Guild) AddRole(roleID string, permission Permission) error {
+	log.Trace("--> guild.Guild.AddRole")
+	defer log.Trace("<-- guild.Guild.AddRole")
 
-	if slices.Contains(guild.AdminRoles, roleName) {
-		log.WithFields(log.Fields{"roleName": roleName, "adminRoles": guild.AdminRoles}).Warn("role already exists")
-		return
+	roleIDs := guild.getRoleIDs(permission)
+	if roleIDs == nil {
+		return ErrInvalidRolePermission
+	}
+	if slices.Contains(*roleIDs, roleID) {
+		log.WithFields(log.Fields{"guild": guild.GuildID, "role": roleID, "permission": permission}).Warn("role already has the permission")
+		return nil
 	}
 
-	guild.AdminRoles = append(guild.AdminRoles, roleName)
+	guild.removeRole(roleID)
+	*roleIDs = append(*roleIDs, roleID)
 	writeGuild(guild)
-	log.WithFields(log.Fields{"roleName": roleName, "adminRoles": guild.AdminRoles}).Info("added admin role")
+	log.WithFields(log.Fields{"guild": guild.GuildID, "role": roleID, "permission": permission}).Info("added role")
+
+	return nil
 }
 
-// RemoveAdminRole removes a role from the list of admin roles for the guild.
-func (guild *Guild) RemoveAdminRole(roleName string) {
-	log.Trace("--> guild.Guild.RemoveAdminRole")
-	defer log.Trace("<-- guild.Guild.RemoveAdminRole")
-
-	for i, role := range guild.AdminRoles {
-		if role == roleName {
-			guild.AdminRoles = append(guild.AdminRoles[:i], guild.AdminRoles[i+1:]...)
-			writeGuild(guild)
-			log.WithFields(log.Fields{"roleName": roleName, "adminRoles": guild.AdminRoles}).Info("removed admin role")
-			return
+// RemoveRole removes all permissions from the role.
+func (guild *Guild) RemoveRole(roleID string) error {
+	log.Trace("--> guild.Guild.RemoveRole")
+	defer log.Trace("<-- guild.Guild.RemoveRole")
+
+	if !guild.removeRole(roleID) {
+		log.WithFields(log.Fields{"guild": guild.GuildID, "role": roleID}).Warn("role not found")
+		return ErrRoleNotFound
+	}
+	writeGuild(guild)
+	log.WithFields(log.Fields{"guild": guild.GuildID, "role": roleID}).Info("removed role")
+
+	return nil
+}
+
+// removeRole removes the role from the list of roles for each permission, returning whether
+// the role was found.
+func (guild *Guild) removeRole(roleID string) bool {
+	found := false
+	for _, permission := range []Permission{PermissionAdmin, PermissionModerator} {
+		roleIDs := guild.getRoleIDs(permission)
+		if idx := slices.Index(*roleIDs, roleID); idx != -1 {
+			*roleIDs = slices.Delete(*roleIDs, idx, idx+1)
+			found = true
 		}
 	}
-	log.WithFields(log.Fields{"roleName": roleName, "adminRoles": guild.AdminRoles}).Warn("role not found")
+	return found
+}
+
+// GetRoles returns the IDs of the roles given the permission.
+func (guild *Guild) GetRoles(permission Permission) []string {
+	log.Trace("--> guild.Guild.GetRoles")
+	defer log.Trace("<-- guild.Guild.GetRoles")
+
+	roleIDs := guild.getRoleIDs(permission)
+	if roleIDs == nil {
+		return nil
+	}
+	return *roleIDs
 }
 
-// GetAdminRoles returns the list of admin roles for the guild.
+// GetAdminRoles returns the IDs of the admin roles for the guild.
 func (guild *Guild) GetAdminRoles() []string {
 	log.Trace("--> guild.Guild.GetAdminRoles")
 	defer log.Trace("<-- guild.Guild.GetAdminRoles")
 
-	return guild.AdminRoles
+	return guild.AdminRoleIDs
 }
 
 // String returns a string representation of the guild.
 func (guild *Guild) String() string {
-	return fmt.Sprintf("Guild{guildID = %s, adminRoles = %v}", guild.GuildID, guild.AdminRoles)
+	return fmt.Sprintf("Guild{guildID = %s, adminRoleIDs = %v, moderatorRoleIDs = %v}", guild.GuildID, guild.AdminRoleIDs, guild.ModeratorRoleIDs)
 }
@@ -0,0 +1,139 @@
+package guild
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// Permission is the level of access a member has to the bot's commands. Each permission includes
+// all the permissions below it.
+type Permission int
+
+const (
+	PermissionMember Permission = iota
+	PermissionModerator
+	PermissionAdmin
+	PermissionOwner
+)
+
+var (
+	permissionNames = []string{"member", "moderator", "admin", "owner"}
+)
+
+// String returns the name of the permission.
+func (permission Permission) String() string {
+	if permission < PermissionMember || permission > PermissionOwner {
+		return "unknown"
+	}
+	return permissionNames[permission]
+}
+
+// ParsePermission returns the permission with the given name.
+func ParsePermission(name string) (Permission, error) {
+	idx := slices.Index(permissionNames, strings.ToLower(name))
+	if idx == -1 {
+		return PermissionMember, ErrInvalidPermission
+	}
+	return Permission(idx), nil
+}
+
+// GetPermission returns the highest permission the member has in the guild. The owner of the guild
+// has the owner permission, members with the Discord "Administrator" permission or an admin role have
+// the admin permission, and members with a moderator role have the moderator permission.
+func GetPermission(s *discordgo.Session, guildID string, memberID string) Permission {
+	log.Trace("--> guild.GetPermission")
+	defer log.Trace("<-- guild.GetPermission")
+
+	discordGuild, err := s.Guild(guildID)
+	if err != nil {
+		log.WithFields(log.Fields{"guildID": guildID, "error": err}).Error("failed to get guild")
+		return PermissionMember
+	}
+	if discordGuild.OwnerID == memberID {
+		return PermissionOwner
+	}
+
+	member, err := s.GuildMember(guildID, memberID)
+	if err != nil {
+		log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "error": err}).Error("failed to get guild member")
+		return PermissionMember
+	}
+	guildRoles := GetGuildRoles(s, guildID)
+	guild := GetGuild(guildID)
+	guild.migrateRoleNames(guildRoles)
+
+	permission := getRolePermission(guild, guildRoles, member.Roles)
+	log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "permission": permission, "memberRoles": member.Roles}).Debug("permission")
+
+	return permission
+}
+
+// HasPermission returns whether the member has, at least, the given permission in the guild.
+func HasPermission(s *discordgo.Session, guildID string, memberID string, permission Permission) bool {
+	log.Trace("--> guild.HasPermission")
+	defer log.Trace("<-- guild.HasPermission")
+
+	return GetPermission(s, guildID, memberID) >= permission
+}
+
+// IsOwner checks if a member is the owner of a guild.
+func IsOwner(s *discordgo.Session, guildID string, memberID string) bool {
+	return HasPermission(s, guildID, memberID, PermissionOwner)
+}
+
+// IsModerator checks if a member is a moderator, or has a higher permission, in a guild.
+func IsModerator(s *discordgo.Session, guildID string, memberID string) bool {
+	return HasPermission(s, guildID, memberID, PermissionModerator)
+}
+
+// getRolePermission returns the highest permission given by the member's roles.
+func getRolePermission(guild *Guild, guildRoles []*discordgo.Role, memberRoleIDs []string) Permission {
+	permission := PermissionMember
+	for _, roleID := range memberRoleIDs {
+		switch {
+		case slices.Contains(guild.AdminRoleIDs, roleID):
+			permission = max(permission, PermissionAdmin)
+		case slices.Contains(guild.ModeratorRoleIDs, roleID):
+			permission = max(permission, PermissionModerator)
+		}
+		for _, role := range guildRoles {
+			if role.ID == roleID && role.Permissions&discordgo.PermissionAdministrator != 0 {
+				permission = max(permission, PermissionAdmin)
+			}
+		}
+	}
+	return permission
+}
+
+// migrateRoleNames replaces the admin role names stored by earlier versions of the bot with the IDs
+// of the guild's roles that have those names. Names that don't match a role are dropped.
+func (guild *Guild) migrateRoleNames(guildRoles []*discordgo.Role) {
+	if len(guild.AdminRoles) == 0 || guildRoles == nil {
+		return
+	}
+	log.Trace("--> guild.Guild.migrateRoleNames")
+	defer log.Trace("<-- guild.Guild.migrateRoleNames")
+
+	for _, roleID := range resolveRoleIDs(guildRoles, guild.AdminRoles) {
+		if !slices.Contains(guild.AdminRoleIDs, roleID) && !slices.Contains(guild.ModeratorRoleIDs, roleID) {
+			guild.AdminRoleIDs = append(guild.AdminRoleIDs, roleID)
+		}
+	}
+	log.WithFields(log.Fields{"guild": guild.GuildID, "roleNames": guild.AdminRoles, "roleIDs": guild.AdminRoleIDs}).Info("migrated admin role names to role IDs")
+	guild.AdminRoles = nil
+	writeGuild(guild)
+}
+
+// resolveRoleIDs returns the IDs of the guild roles with the given names.
+func resolveRoleIDs(guildRoles []*discordgo.Role, roleNames []string) []string {
+	roleIDs := make([]string, 0, len(roleNames))
+	for _, role := range guildRoles {
+		if slices.Contains(roleNames, role.Name) {
+			roleIDs = append(roleIDs, role.ID)
+		}
+	}
+	return roleIDs
+}
@@ -0,0 +1,55 @@
+package guild
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParsePermission(t *testing.T) {
+	for _, permission := range []Permission{PermissionMember, PermissionModerator, PermissionAdmin, PermissionOwner} {
+		parsed, err := ParsePermission(permission.String())
+		if err != nil || parsed != permission {
+			t.Errorf("expected to parse %s, got %s (%v)", permission, parsed, err)
+		}
+	}
+	if _, err := ParsePermission("superuser"); err != ErrInvalidPermission {
+		t.Errorf("expected an invalid permission, got %v", err)
+	}
+}
+
+func TestRolePermission(t *testing.T) {
+	guildRoles := []*discordgo.Role{
+		{ID: "1", Name: "Admin"},
+		{ID: "2", Name: "Mod"},
+		{ID: "3", Name: "Staff", Permissions: discordgo.PermissionAdministrator},
+		{ID: "4", Name: "Admin"},
+	}
+	guild := &Guild{
+		GuildID:          GUILD_ID,
+		AdminRoleIDs:     []string{"1"},
+		ModeratorRoleIDs: []string{"2"},
+	}
+
+	tests := []struct {
+		roles    []string
+		expected Permission
+	}{
+		{[]string{}, PermissionMember},
+		{[]string{"2"}, PermissionModerator},
+		{[]string{"2", "1"}, PermissionAdmin},
+		{[]string{"3"}, PermissionAdmin},
+		{[]string{"4"}, PermissionMember}, // Same name as an admin role, but a different ID
+	}
+	for _, tc := range tests {
+		if permission := getRolePermission(guild, guildRoles, tc.roles); permission != tc.expected {
+			t.Errorf("expected %s for roles %v, got %s", tc.expected, tc.roles, permission)
+		}
+	}
+
+	roleIDs := resolveRoleIDs(guildRoles, []string{"Admin", "Missing"})
+	if !slices.Equal(roleIDs, []string{"1", "4"}) {
+		t.Errorf("expected role IDs [1 4], got %v", roleIDs)
+	}
+}
@@ -0,0 +1,53 @@
+package guild
+
+import (
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// IsPluginEnabled returns whether the plugin is enabled for the guild. Plugins are enabled unless
+// they have been disabled for the guild.
+func (guild *Guild) IsPluginEnabled(plugin string) bool {
+	return !slices.Contains(guild.DisabledPlugins, plugin)
+}
+
+// EnablePlugin enables the plugin for the guild.
+func (guild *Guild) EnablePlugin(plugin string) error {
+	log.Trace("--> guild.Guild.EnablePlugin")
+	defer log.Trace("<-- guild.Guild.EnablePlugin")
+
+	idx := slices.Index(guild.DisabledPlugins, plugin)
+	if idx == -1 {
+		return ErrPluginAlreadyEnabled
+	}
+	guild.DisabledPlugins = slices.Delete(guild.DisabledPlugins, idx, idx+1)
+	writeGuild(guild)
+	log.WithFields(log.Fields{"guild": guild.GuildID, "plugin": plugin}).Info("enabled plugin")
+
+	return nil
+}
+
+// DisablePlugin disables the plugin for the guild.
+func (guild *Guild) DisablePlugin(plugin string) error {
+	log.Trace("--> guild.Guild.DisablePlugin")
+	defer log.Trace("<-- guild.Guild.DisablePlugin")
+
+	if !guild.IsPluginEnabled(plugin) {
+		return ErrPluginAlreadyDisabled
+	}
+	guild.DisabledPlugins = append(guild.DisabledPlugins, plugin)
+	writeGuild(guild)
+	log.WithFields(log.Fields{"guild": guild.GuildID, "plugin": plugin}).Info("disabled plugin")
+
+	return nil
+}
+
+// IsPluginEnabled returns whether the plugin is enabled for the guild.
+func IsPluginEnabled(guildID string, plugin string) bool {
+	guild := readGuild(guildID)
+	if guild == nil {
+		return true
+	}
+	return guild.IsPluginEnabled(plugin)
+}
@@ -0,0 +1,36 @@
+package guild
+
+import "testing"
+
+func TestDisablePlugin(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if !IsPluginEnabled(GUILD_ID, "heist") {
+		t.Error("expected plugins to be enabled by default")
+	}
+
+	guild := GetGuild(GUILD_ID)
+	if err := guild.DisablePlugin("heist"); err != nil {
+		t.Errorf("unexpected error disabling the plugin: %v", err)
+	}
+	if err := guild.DisablePlugin("heist"); err != ErrPluginAlreadyDisabled {
+		t.Errorf("expected the plugin to already be disabled, got %v", err)
+	}
+	if IsPluginEnabled(GUILD_ID, "heist") {
+		t.Error("expected the heist plugin to be disabled")
+	}
+	if !IsPluginEnabled(GUILD_ID, "race") {
+		t.Error("expected the race plugin to be enabled")
+	}
+
+	if err := guild.EnablePlugin("heist"); err != nil {
+		t.Errorf("unexpected error enabling the plugin: %v", err)
+	}
+	if err := guild.EnablePlugin("heist"); err != ErrPluginAlreadyEnabled {
+		t.Errorf("expected the plugin to already be enabled, got %v", err)
+	}
+	if !IsPluginEnabled(GUILD_ID, "heist") {
+		t.Error("expected the heist plugin to be enabled after re-enabling it")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
 	log "github.com/sirupsen/logrus"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 var (
@@ -18,24 +17,13 @@ func SetDB(database *mongo.MongoDB) {
 	db = database
 }
 
-// GetAdminRoles returns the list of admin roles for a given guild.
-// If the guild is not found, it returns nil.
+// GetAdminRoles returns the IDs of the admin roles for a given guild.
 // If there are no admin roles, it returns an empty slice.
 func GetAdminRoles(guildID string) []string {
 	log.Trace("--> role.GetAdminRoles")
 	defer log.Trace("<-- role.GetAdminRoles")
 
-	filter := bson.M{"guild_id": guildID}
-	server := &Guild{}
-	err := db.FindOne(GUILD_COLLECTION, filter, server)
-	if err != nil {
-		log.WithFields(log.Fields{"guild": guildID}).Debug("server not found in the database")
-	}
-	if server.GuildID == "" {
-		server = newGuild(guildID)
-	}
-
-	return server.AdminRoles
+	return GetGuild(guildID).AdminRoleIDs
 }
 
 // GetGuildRoles returns the list of roles for a guild.
@@ -83,22 +71,12 @@ func CheckAdminRole(adminRoles []string, memberRoles []string) bool {
 }
 
 // IsAdmin checks if a member is an admin in a guild.
-// It returns true if the member has any admin role in the server.
-// It returns false if the member does not have any admin role.
+// It returns true if the member is the owner of the guild, has the Discord "Administrator" permission,
+// or has any admin role in the server.
+// It returns false if the member does not have any of these.
 func IsAdmin(s *discordgo.Session, guildID string, memberID string) bool {
 	log.Trace("--> guild.IsAdmin")
 	defer log.Trace("<-- guild.IsAdmin")
 
-	guildRoles := GetGuildRoles(s, guildID)
-	member, err := s.GuildMember(guildID, memberID)
-	if err != nil {
-		log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "error": err}).Error("failed to get guild member")
-		return false
-	}
-	memberRoles := GetMemberRoles(guildRoles, member.Roles)
-	adminRoles := GetAdminRoles(guildID)
-	isAdmin := CheckAdminRole(adminRoles, memberRoles)
-	log.WithFields(log.Fields{"guildID": guildID, "memberID": memberID, "isAdmin": isAdmin, "adminRoles": adminRoles, "memberRoles": memberRoles}).Debug("isAdmin")
-
-	return isAdmin
+	return HasPermission(s, guildID, memberID, PermissionAdmin)
 }
@@ -51,8 +51,8 @@ func TestCheckAdminRoles(t *testing.T) {
 		return
 	}
 
-	guildRoles := []string{"Role 1", "Role 2", "Admin", "Role 3"}
-	if !CheckAdminRole(guildRoles, adminRoles) {
+	memberRoles := []string{"1", "2", "100", "3"}
+	if !CheckAdminRole(adminRoles, memberRoles) {
 		t.Error("admin roles not found")
 		return
 	}
@@ -60,12 +60,12 @@ func TestCheckAdminRoles(t *testing.T) {
 
 func setup() {
 	type Server struct {
-		GuildID    string   `bson:"guild_id"`
-		AdminRoles []string `bson:"admin_roles"`
+		GuildID      string   `bson:"guild_id"`
+		AdminRoleIDs []string `bson:"admin_role_ids"`
 	}
 	server := &Server{
-		GuildID:    GUILD_ID,
-		AdminRoles: []string{"Admin", "Admins", "Administrator", "Mod", "Mods", "Moderator"},
+		GuildID:      GUILD_ID,
+		AdminRoleIDs: []string{"100", "101"},
 	}
 	db.UpdateOrInsert(GUILD_COLLECTION, bson.M{"guild_id": GUILD_ID}, server)
 }
@@ -13,7 +13,14 @@ type Mute struct {
 	everyoneID          string
 	everyonePermissions discordgo.PermissionOverwrite
 	s                   *discordgo.Session
-	i                   *discordgo.InteractionCreate
+}
+
+// MuteState is the information required to unmute a channel. It may be saved and later used
+// to restore the channel's permissions, such as when the bot is restarted while a channel is muted.
+type MuteState struct {
+	ChannelID  string                        `json:"channel_id" bson:"channel_id"`
+	EveryoneID string                        `json:"everyone_id" bson:"everyone_id"`
+	Overwrite  discordgo.PermissionOverwrite `json:"overwrite" bson:"overwrite"`
 }
 
 // NewChannelMute creates a channelMute for the given session and interaction.
@@ -30,7 +37,6 @@ func NewChannelMute(s *discordgo.Session, i *discordgo.InteractionCreate) *Mute
 
 	c := Mute{
 		s:       s,
-		i:       i,
 		channel: channel,
 	}
 
@@ -51,9 +57,33 @@ func NewChannelMute(s *discordgo.Session, i *discordgo.InteractionCreate) *Mute
 	return &c
 }
 
+// RestoreChannelMute recreates a channel mute from a previously saved state, allowing the channel
+// to be unmuted.
+func RestoreChannelMute(s *discordgo.Session, state *MuteState) *Mute {
+	c := Mute{
+		s:                   s,
+		channel:             &discordgo.Channel{ID: state.ChannelID},
+		everyoneID:          state.EveryoneID,
+		everyonePermissions: state.Overwrite,
+	}
+
+	return &c
+}
+
+// GetState returns the information needed to restore the channel permissions when unmuting the channel.
+func (c *Mute) GetState() *MuteState {
+	state := &MuteState{
+		ChannelID:  c.channel.ID,
+		EveryoneID: c.everyoneID,
+		Overwrite:  c.everyonePermissions,
+	}
+
+	return state
+}
+
 // MuteChannel sets the channel so that `@everyone`	 can't send messages to the channel.
 func (c *Mute) MuteChannel() {
-	err := c.s.ChannelPermissionSet(c.i.ChannelID, c.everyoneID, discordgo.PermissionOverwriteTypeRole, 0, discordgo.PermissionSendMessages)
+	err := c.s.ChannelPermissionSet(c.channel.ID, c.everyoneID, discordgo.PermissionOverwriteTypeRole, 0, discordgo.PermissionSendMessages)
 	if err != nil {
 		log.Warning("Failed to mute the channel, error:", err)
 	}
@@ -62,19 +92,19 @@ func (c *Mute) MuteChannel() {
 // UnmuteChannel resets the permissions for `@everyone` to what they were before the channel was muted.
 func (c *Mute) UnmuteChannel() {
 	if c.everyonePermissions.ID != "" {
-		err := c.s.ChannelPermissionDelete(c.i.ChannelID, c.everyoneID)
+		err := c.s.ChannelPermissionDelete(c.channel.ID, c.everyoneID)
 		if err != nil {
 			log.Warning("Failed to delete the mute for the channel, error:", err)
 			return
 		}
-		log.WithFields(log.Fields{"channelID": c.i.ChannelID}).Info("deleted the mute permissions for the channel")
+		log.WithFields(log.Fields{"channelID": c.channel.ID}).Info("deleted the mute permissions for the channel")
 	} else {
 		allow := int64(discordgo.PermissionSendMessages)
-		err := c.s.ChannelPermissionSet(c.i.ChannelID, c.everyoneID, c.everyonePermissions.Type, allow, c.everyonePermissions.Deny)
+		err := c.s.ChannelPermissionSet(c.channel.ID, c.everyoneID, c.everyonePermissions.Type, allow, c.everyonePermissions.Deny)
 		if err != nil {
 			log.Warning("Failed to unmute the channel, error:", err)
 			return
 		}
-		log.WithFields(log.Fields{"channelID": c.i.ChannelID}).Info("reset the mute permissions for the channel")
+		log.WithFields(log.Fields{"channelID": c.channel.ID}).Info("reset the mute permissions for the channel")
 	}
 }
@@ -0,0 +1,130 @@
+package leaderboard
+
+import (
+	"time"
+
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SEASON_HISTORY_SIZE = 10 // Number of seasons listed by `/lb history`
+)
+
+// SeasonArchive is the final standings for a leaderboard season in a guild.
+type SeasonArchive struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string             `json:"guild_id" bson:"guild_id"`
+	Season    int                `json:"season" bson:"season"`
+	Title     string             `json:"title" bson:"title"`
+	Start     time.Time          `json:"start" bson:"start"`
+	End       time.Time          `json:"end" bson:"end"`
+	Standings []*SeasonStanding  `json:"standings" bson:"standings"`
+}
+
+// SeasonStanding is the final position of a member at the end of a season.
+type SeasonStanding struct {
+	Rank     int    `json:"rank" bson:"rank"`
+	MemberID string `json:"member_id" bson:"member_id"`
+	Name     string `json:"name" bson:"name"`
+	Balance  int    `json:"balance" bson:"balance"`
+}
+
+// archiveSeason saves the final standings for the season that ends at `end`.
+func archiveSeason(lb *Leaderboard, end time.Time) *SeasonArchive {
+	log.Trace("--> leaderboard.archiveSeason")
+	defer log.Trace("<-- leaderboard.archiveSeason")
+
+	filter := bson.D{
+		{Key: "guild_id", Value: lb.GuildID},
+		{Key: "monthly_balance", Value: bson.D{{Key: "$ne", Value: 0}}},
+	}
+	sort := bson.D{{Key: "monthly_balance", Value: -1}, {Key: "_id", Value: 1}}
+	accounts := bank.GetAccounts(lb.GuildID, filter, sort, 0)
+
+	archive := &SeasonArchive{
+		GuildID:   lb.GuildID,
+		Season:    countSeasonArchives(lb.GuildID) + 1,
+		Title:     lb.seasonTitle(lb.LastSeason),
+		Start:     lb.LastSeason,
+		End:       end,
+		Standings: make([]*SeasonStanding, 0, len(accounts)),
+	}
+	for idx, account := range accounts {
+		member := guild.GetMember(lb.GuildID, account.MemberID)
+		standing := &SeasonStanding{
+			Rank:     idx + 1,
+			MemberID: account.MemberID,
+			Name:     member.Name,
+			Balance:  account.MonthlyBalance,
+		}
+		archive.Standings = append(archive.Standings, standing)
+	}
+
+	err := writeSeasonArchive(archive)
+	if err != nil {
+		return nil
+	}
+	log.WithFields(log.Fields{"guild": lb.GuildID, "season": archive.Season, "members": len(archive.Standings)}).Info("archived leaderboard season")
+
+	return archive
+}
+
+// getSeasonArchive returns the archive for the given season number.
+func getSeasonArchive(guildID string, season int) (*SeasonArchive, error) {
+	log.Trace("--> leaderboard.getSeasonArchive")
+	defer log.Trace("<-- leaderboard.getSeasonArchive")
+
+	return readSeasonArchive(guildID, season)
+}
+
+// getSeasonArchives returns the most recent seasons for the guild, newest first.
+func getSeasonArchives(guildID string, limit int) []*SeasonArchive {
+	log.Trace("--> leaderboard.getSeasonArchives")
+	defer log.Trace("<-- leaderboard.getSeasonArchives")
+
+	return readSeasonArchives(guildID, limit)
+}
+
+// getBestSeason returns the season in which the member finished in the highest position, along with
+// the member's standing in that season. If the member finished in the same position in multiple seasons,
+// the season with the highest balance is returned.
+func getBestSeason(guildID string, memberID string) (*SeasonArchive, *SeasonStanding) {
+	log.Trace("--> leaderboard.getBestSeason")
+	defer log.Trace("<-- leaderboard.getBestSeason")
+
+	var bestArchive *SeasonArchive
+	var bestStanding *SeasonStanding
+	for _, archive := range readMemberSeasonArchives(guildID, memberID) {
+		standing := archive.getStanding(memberID)
+		if standing == nil {
+			continue
+		}
+		if bestStanding == nil ||
+			standing.Rank < bestStanding.Rank ||
+			(standing.Rank == bestStanding.Rank && standing.Balance > bestStanding.Balance) {
+			bestArchive = archive
+			bestStanding = standing
+		}
+	}
+
+	return bestArchive, bestStanding
+}
+
+// getStanding returns the standing of the member in the season, or `nil` if the member didn't place.
+func (archive *SeasonArchive) getStanding(memberID string) *SeasonStanding {
+	for _, standing := range archive.Standings {
+		if standing.MemberID == memberID {
+			return standing
+		}
+	}
+	return nil
+}
+
+// getTop returns up to `n` of the highest ranked members for the season.
+func (archive *SeasonArchive) getTop(n int) []*SeasonStanding {
+	return archive.Standings[:min(n, len(archive.Standings))]
+}
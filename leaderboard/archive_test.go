@@ -0,0 +1,30 @@
+package leaderboard
+
+import (
+	"testing"
+)
+
+func TestSeasonArchiveStandings(t *testing.T) {
+	archive := &SeasonArchive{
+		GuildID: "12345",
+		Season:  1,
+		Standings: []*SeasonStanding{
+			{Rank: 1, MemberID: "1", Balance: 3000},
+			{Rank: 2, MemberID: "2", Balance: 2000},
+			{Rank: 3, MemberID: "3", Balance: 1000},
+		},
+	}
+
+	if top := archive.getTop(2); len(top) != 2 || top[0].MemberID != "1" {
+		t.Errorf("expected the top 2 members, got %v", top)
+	}
+	if top := archive.getTop(10); len(top) != 3 {
+		t.Errorf("expected all 3 members, got %d", len(top))
+	}
+	if standing := archive.getStanding("2"); standing == nil || standing.Rank != 2 {
+		t.Errorf("expected member 2 to finish second, got %v", standing)
+	}
+	if standing := archive.getStanding("4"); standing != nil {
+		t.Errorf("expected member 4 to not have a standing, got %v", standing)
+	}
+}
@@ -0,0 +1,172 @@
+package leaderboard
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"slices"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CARD_FILE_NAME   = "leaderboard.png"
+	CARD_WIDTH       = 720
+	CARD_PADDING     = 16
+	CARD_TITLE_SCALE = 3
+	CARD_TEXT_SCALE  = 2
+	CARD_ROW_HEIGHT  = 32
+	CARD_NAME_LENGTH = 32 // Maximum number of characters of a name shown on a card
+)
+
+var (
+	cardBackground = color.RGBA{0x2B, 0x2D, 0x31, 0xFF}
+	cardRowEven    = color.RGBA{0x31, 0x33, 0x38, 0xFF}
+	cardRowOdd     = color.RGBA{0x38, 0x3A, 0x40, 0xFF}
+	cardHighlight  = color.RGBA{0x4E, 0x5D, 0x94, 0xFF}
+	cardTitle      = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	cardText       = color.RGBA{0xDB, 0xDE, 0xE1, 0xFF}
+	cardDivider    = color.RGBA{0x80, 0x84, 0x8E, 0xFF}
+	cardUp         = color.RGBA{0x57, 0xF2, 0x87, 0xFF}
+	cardDown       = color.RGBA{0xED, 0x42, 0x45, 0xFF}
+	cardMedals     = []color.RGBA{
+		{0xF1, 0xC4, 0x0F, 0xFF}, // gold
+		{0xBD, 0xC3, 0xC7, 0xFF}, // silver
+		{0xCD, 0x7F, 0x32, 0xFF}, // bronze
+	}
+)
+
+// cardRow is a single row shown on a leaderboard card.
+type cardRow struct {
+	Rank      int    // Rank of the member, or 0 for a row separating non-consecutive ranks
+	Name      string // Display name of the member
+	Balance   string // Formatted balance of the member
+	Movement  int    // Number of places moved up (positive) or down (negative) since the previous snapshot
+	Highlight bool   // Whether the row belongs to the member who requested the card
+}
+
+// renderCard draws the leaderboard as a PNG image.
+func renderCard(title string, rows []*cardRow) ([]byte, error) {
+	log.Trace("--> leaderboard.renderCard")
+	defer log.Trace("<-- leaderboard.renderCard")
+
+	titleHeight := GLYPH_HEIGHT*CARD_TITLE_SCALE + 2*CARD_PADDING
+	height := titleHeight + max(len(rows), 1)*CARD_ROW_HEIGHT + CARD_PADDING
+	img := image.NewRGBA(image.Rect(0, 0, CARD_WIDTH, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{cardBackground}, image.Point{}, draw.Src)
+
+	drawText(img, CARD_PADDING, CARD_PADDING, title, CARD_TITLE_SCALE, cardTitle)
+
+	textOffset := (CARD_ROW_HEIGHT - GLYPH_HEIGHT*CARD_TEXT_SCALE) / 2
+	rankX := CARD_PADDING + 8
+	nameX := rankX + textWidth("0000", CARD_TEXT_SCALE) + CARD_PADDING
+	arrowX := CARD_WIDTH - CARD_PADDING - 48
+	balanceRight := arrowX - CARD_PADDING
+
+	if len(rows) == 0 {
+		drawText(img, nameX, titleHeight+textOffset, "No one has made the leaderboard yet.", CARD_TEXT_SCALE, cardText)
+	}
+	for idx, row := range rows {
+		top := titleHeight + idx*CARD_ROW_HEIGHT
+		background := cardRowEven
+		if idx%2 == 1 {
+			background = cardRowOdd
+		}
+		if row.Highlight {
+			background = cardHighlight
+		}
+		rowRect := image.Rect(CARD_PADDING, top, CARD_WIDTH-CARD_PADDING, top+CARD_ROW_HEIGHT)
+		draw.Draw(img, rowRect, &image.Uniform{background}, image.Point{}, draw.Src)
+
+		textY := top + textOffset
+		if row.Rank == 0 {
+			drawText(img, nameX, textY, "...", CARD_TEXT_SCALE, cardDivider)
+			continue
+		}
+
+		rankColor := cardText
+		if row.Rank <= len(cardMedals) {
+			rankColor = cardMedals[row.Rank-1]
+		}
+		drawText(img, rankX, textY, strconv.Itoa(row.Rank), CARD_TEXT_SCALE, rankColor)
+		drawText(img, nameX, textY, truncateName(row.Name), CARD_TEXT_SCALE, cardText)
+		drawText(img, balanceRight-textWidth(row.Balance, CARD_TEXT_SCALE), textY, row.Balance, CARD_TEXT_SCALE, cardText)
+		drawMovement(img, arrowX, top, row.Movement)
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		log.WithField("error", err).Error("unable to encode the leaderboard card")
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawMovement draws an arrow showing how many places a member moved since the previous snapshot.
+// Nothing is drawn if the member didn't move.
+func drawMovement(img *image.RGBA, x int, top int, movement int) {
+	if movement == 0 {
+		return
+	}
+
+	c := cardUp
+	if movement < 0 {
+		c = cardDown
+	}
+	// Draw a triangle 10 pixels wide, pointing up or down
+	const size = 5
+	centerY := top + CARD_ROW_HEIGHT/2
+	for row := 0; row < size; row++ {
+		y := centerY - size/2 + row
+		if movement < 0 {
+			y = centerY + size/2 - row
+		}
+		for col := -row; col <= row; col++ {
+			img.Set(x+size+col, y, c)
+			img.Set(x+size+col, y+1, c)
+		}
+	}
+
+	places := strconv.Itoa(max(movement, -movement))
+	drawText(img, x+2*size+4, top+(CARD_ROW_HEIGHT-GLYPH_HEIGHT*CARD_TEXT_SCALE)/2, places, CARD_TEXT_SCALE, c)
+}
+
+// truncateName shortens a name so that it fits on a leaderboard card.
+func truncateName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= CARD_NAME_LENGTH {
+		return name
+	}
+	return string(runes[:CARD_NAME_LENGTH-2]) + ".."
+}
+
+// attachCard renders the leaderboard card and shows it as the image for the embed. If the card can't be
+// rendered, the embed is left unchanged so that the text version of the leaderboard is shown instead.
+// The text version is also kept when a name has characters that aren't in the font used for the card,
+// such as accents, CJK characters or emoji, so that the name is shown correctly.
+func attachCard(embed *discordgo.MessageEmbed, rows []*cardRow) []*discordgo.File {
+	data, err := renderCard(embed.Title, rows)
+	if err != nil {
+		return nil
+	}
+
+	if !slices.ContainsFunc(rows, func(row *cardRow) bool { return !canDrawText(row.Name) }) {
+		embed.Fields = nil
+	}
+	embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + CARD_FILE_NAME}
+	files := []*discordgo.File{
+		{
+			Name:        CARD_FILE_NAME,
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(data),
+		},
+	}
+
+	return files
+}
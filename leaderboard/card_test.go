@@ -0,0 +1,68 @@
+package leaderboard
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRenderCard(t *testing.T) {
+	rows := []*cardRow{
+		{Rank: 1, Name: "first", Balance: "10,000", Movement: 2},
+		{Rank: 2, Name: "a member with a very long display name that won't fit", Balance: "5,000", Movement: -1},
+		{},
+		{Rank: 25, Name: "ünïcödé", Balance: "100", Highlight: true},
+	}
+
+	data, err := renderCard("Current Leaderboard", rows)
+	if err != nil {
+		t.Fatalf("unable to render the card: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unable to decode the card: %v", err)
+	}
+	if img.Bounds().Dx() != CARD_WIDTH {
+		t.Errorf("expected a card %d pixels wide, got %d", CARD_WIDTH, img.Bounds().Dx())
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	name := "a member with a very long display name that won't fit"
+	truncated := truncateName(name)
+	if len([]rune(truncated)) != CARD_NAME_LENGTH {
+		t.Errorf("expected the name to be truncated to %d characters, got %q", CARD_NAME_LENGTH, truncated)
+	}
+	if truncateName("short") != "short" {
+		t.Errorf("expected a short name to be unchanged")
+	}
+}
+
+func TestAttachCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepFields bool
+	}{
+		{"member", false},
+		{"ünïcödé", true},
+		{"メンバー", true},
+		{"member 🏆", true},
+	}
+
+	for _, tc := range tests {
+		embed := &discordgo.MessageEmbed{
+			Title:  "Current Leaderboard",
+			Fields: []*discordgo.MessageEmbedField{{Value: tc.name}},
+		}
+		rows := []*cardRow{{Rank: 1, Name: tc.name, Balance: "100"}}
+		files := attachCard(embed, rows)
+		if len(files) != 1 || embed.Image == nil {
+			t.Errorf("expected the card to be attached for %q", tc.name)
+		}
+		if keepFields := len(embed.Fields) != 0; keepFields != tc.keepFields {
+			t.Errorf("expected the text fields to be kept for %q to be %v, got %v", tc.name, tc.keepFields, keepFields)
+		}
+	}
+}
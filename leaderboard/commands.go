@@ -7,28 +7,30 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/olekukonko/tablewriter"
 	"github.com/rbrabson/goblin/bank"
-	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/internal/discmsg"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
 var (
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"lb-admin": leaderboardAdmin,
-		"lb":       leaderboard,
+	componentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+		"lb_previous": leaderboardPrevious,
+		"lb_next":     leaderboardNext,
+		"lb_me":       leaderboardJumpToMe,
 	}
 
-	adminCommands = []*discordgo.ApplicationCommand{
-		{
+	adminRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "lb-admin",
 			Description: "Commands used to interact with the leaderboard for this server.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "channel",
-					Description: "Sets the channel ID where the monthly leaderboard is published at the end of the month.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Description: "Sets the channel ID where the leaderboard is published at the end of each season.",
+					Handler:     setLeaderboardChannel,
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
@@ -38,91 +40,148 @@ var (
 						},
 					},
 				},
+				{
+					Name:        "rewards",
+					Description: "Manages the rewards given to the top finishers of each season.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "list",
+							Description: "Lists the rewards given at the end of each season.",
+							Handler:     listRewards,
+						},
+						{
+							Name:        "set",
+							Description: "Sets the reward for finishing a season at the given rank.",
+							Handler:     setReward,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "rank",
+									Description: "The final rank, from 1 to 10.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "amount",
+									Description: "The credits deposited into the member's account.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role given to the member until the end of the next season.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "remove",
+							Description: "Removes the reward for the given rank.",
+							Handler:     removeReward,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "rank",
+									Description: "The final rank, from 1 to 10.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "season",
+					Description: "Sets the length of a season and when it rolls over.",
+					Handler:     setLeaderboardSeason,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "length",
+							Description: "The length of the season.",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Weekly", Value: SEASON_WEEKLY},
+								{Name: "Monthly", Value: SEASON_MONTHLY},
+								{Name: "Custom", Value: SEASON_CUSTOM},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "days",
+							Description: "The number of days in a custom season.",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "timezone",
+							Description: "The timezone used for the rollover, such as America/New_York.",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "hour",
+							Description: "The hour of the day, from 0 to 23, at which the season rolls over.",
+							Required:    false,
+						},
+					},
+				},
 				{
 					Name:        "info",
 					Description: "Gets information about the leaderboard configuration.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     getLeaderboardInfo,
 				},
 			},
 		},
-	}
-	memberCommands = []*discordgo.ApplicationCommand{
-		{
+	)
+	memberRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "lb",
 			Description: "Commands used to retrieve leaderboards on this server.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
 				{
 					Name:        "current",
 					Description: "Gets the current economy leaderboard.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     currentLeaderboard,
 				},
 				{
 					Name:        "monthly",
-					Description: "Gets the monthly economy leaderboard.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Description: "Gets the economy leaderboard for the current season.",
+					Handler:     monthlyLeaderboard,
 				},
 				{
 					Name:        "lifetime",
 					Description: "Gets the lifetime economy leaderboard.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     lifetimeLeaderboard,
+				},
+				{
+					Name:        "history",
+					Description: "Gets the final standings for past seasons.",
+					Handler:     seasonHistory,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "season",
+							Description: "The season number. If not provided, the most recent seasons are listed.",
+							Required:    false,
+						},
+					},
 				},
 				{
 					Name:        "rank",
 					Description: "Gets the member rank for the leaderboards.",
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Handler:     rank,
 				},
 			},
 		},
-	}
+	)
 )
 
-// leaderboardAdmin updates the leaderboardAdmin channel.
-func leaderboardAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> leaderboard.leaderboard")
-	defer log.Trace("<-- leaderboard.leaderboard")
-
-	p := discmsg.GetPrinter(language.AmericanEnglish)
-
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
-	options := i.ApplicationCommandData().Options
-	if options[0].Name == "channel" {
-		setLeaderboardChannel(s, i)
-	} else if options[0].Name == "info" {
-		getLeaderboardInfo(s, i)
-	}
-}
-
-// leaderboard handles the leaderboard commands.
-func leaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> leaderboard.leaderboard")
-	defer log.Trace("<-- leaderboard.leaderboard")
-
-	options := i.ApplicationCommandData().Options
-	switch options[0].Name {
-	case "current":
-		currentLeaderboard(s, i)
-	case "monthly":
-		monthlyLeaderboard(s, i)
-	case "lifetime":
-		lifetimeLeaderboard(s, i)
-	case "rank":
-		rank(s, i)
-	}
-}
-
 // currentLeaderboard returns the top ranked accounts for the current balance.
 func currentLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> leader.currentLeaderboard")
 	defer log.Trace("<-- leaderboard.currentLeaderboard")
 
-	lb := getLeaderboard(i.GuildID)
-	leaderboard := lb.getCurrentLeaderboard()
-	sendLeaderboard(s, i, "Current Leaderboard", leaderboard)
+	sendLeaderboardPage(s, i, getBalanceMetric("current"))
 }
 
 // monthlyLeaderboard returns the top ranked accounts for the current months.
@@ -130,9 +189,7 @@ func monthlyLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> leaderboard.monthlyLeaderboard")
 	defer log.Trace("<-- leaderboard.monthlyLeaderboard")
 
-	lb := getLeaderboard(i.GuildID)
-	leaderboard := lb.getMonthlyLeaderboard()
-	sendLeaderboard(s, i, "Monthly Leaderboard", leaderboard)
+	sendLeaderboardPage(s, i, getBalanceMetric("monthly"))
 }
 
 // lifetimeLeaderboard returns the top ranked accounts for the lifetime of the server.
@@ -140,9 +197,7 @@ func lifetimeLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> leaderboard.lifetimeLeaderboard")
 	defer log.Trace("<-- leaderboard.lifetimeLeaderboard")
 
-	lb := getLeaderboard(i.GuildID)
-	leaderboard := lb.getLifetimeLeaderboard()
-	sendLeaderboard(s, i, "Lifetime Leaderboard", leaderboard)
+	sendLeaderboardPage(s, i, getBalanceMetric("lifetime"))
 }
 
 // setLeaderboardChannel sets the server channel to which the monthly leaderboard is published.
@@ -160,6 +215,110 @@ func setLeaderboardChannel(s *discordgo.Session, i *discordgo.InteractionCreate)
 	discmsg.SendResponse(s, i, resp)
 }
 
+// setLeaderboardSeason sets the length of the season and when it rolls over.
+func setLeaderboardSeason(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> setLeaderboardSeason")
+	defer log.Trace("<-- setLeaderboardSeason")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	lb := getLeaderboard(i.GuildID)
+	length := lb.getSeasonLength()
+	days := lb.SeasonDays
+	timezone := lb.getLocation().String()
+	hour := lb.RolloverHour
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		switch option.Name {
+		case "length":
+			length = option.StringValue()
+		case "days":
+			days = int(option.IntValue())
+		case "timezone":
+			timezone = option.StringValue()
+		case "hour":
+			hour = int(option.IntValue())
+		}
+	}
+
+	err := lb.setSeason(length, days, timezone, hour)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to set the season: %s.", err))
+		return
+	}
+
+	resp := p.Sprintf("The leaderboard season is now %s. The current season ends <t:%d:F>.", formatSeason(lb), lb.nextSeason(lb.LastSeason).Unix())
+	discmsg.SendResponse(s, i, resp)
+}
+
+// listRewards lists the rewards given to the top finishers of each season.
+func listRewards(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.listRewards")
+	defer log.Trace("<-- leaderboard.listRewards")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	lb := getLeaderboard(i.GuildID)
+	if len(lb.Rewards) == 0 {
+		discmsg.SendEphemeralResponse(s, i, "No season rewards are configured.")
+		return
+	}
+
+	var sb strings.Builder
+	for _, reward := range lb.Rewards {
+		sb.WriteString(p.Sprintf("**#%d**%s\n", reward.Rank, formatReward(reward)))
+	}
+	discmsg.SendEphemeralResponse(s, i, sb.String())
+}
+
+// setReward sets the reward for finishing a season at the given rank.
+func setReward(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.setReward")
+	defer log.Trace("<-- leaderboard.setReward")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	var rank, amount int
+	var roleID string
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "rank":
+			rank = int(option.IntValue())
+		case "amount":
+			amount = int(option.IntValue())
+		case "role":
+			roleID = option.RoleValue(s, i.GuildID).ID
+		}
+	}
+
+	lb := getLeaderboard(i.GuildID)
+	err := lb.setReward(rank, amount, roleID)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to set the reward: %s.", err))
+		return
+	}
+
+	resp := p.Sprintf("The reward for finishing a season at #%d is now%s.", rank, formatReward(lb.getReward(rank)))
+	discmsg.SendResponse(s, i, resp)
+}
+
+// removeReward removes the reward for the given rank.
+func removeReward(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.removeReward")
+	defer log.Trace("<-- leaderboard.removeReward")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	rank := int(i.ApplicationCommandData().Options[0].Options[0].Options[0].IntValue())
+	lb := getLeaderboard(i.GuildID)
+	err := lb.removeReward(rank)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to remove the reward: %s.", err))
+		return
+	}
+
+	discmsg.SendResponse(s, i, p.Sprintf("The reward for finishing a season at #%d has been removed.", rank))
+}
+
 // getLeaderboardInfo returns the leaderboard configuration for the server.
 func getLeaderboardInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> getLeaderboardInfo")
@@ -168,28 +327,23 @@ func getLeaderboardInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	p := discmsg.GetPrinter(language.AmericanEnglish)
 
 	lb := getLeaderboard(i.GuildID)
-	resp := p.Sprintf("channel ID for the monthly leaderboard is %s.", lb.ChannelID)
+	resp := p.Sprintf("**Channel ID**: %s\n**Season**: %s\n**Current Season Ends**: <t:%d:F>\n",
+		lb.ChannelID,
+		formatSeason(lb),
+		lb.nextSeason(lb.LastSeason).Unix(),
+	)
 	discmsg.SendEphemeralResponse(s, i, resp)
 }
 
-// sendLeaderboard is a utility function that sends an economy leaderboard to Discord.
-func sendLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate, title string, accounts []*bank.Account) {
-	log.Trace("--> sendLeaderboard")
-	defer log.Trace("<-- sendLeaderboard")
-
-	// Make sure the guild member's name is updated
-	_ = guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.DisplayName())
-
+// formatSeason returns a description of the season configured for the leaderboard.
+func formatSeason(lb *Leaderboard) string {
 	p := discmsg.GetPrinter(language.AmericanEnglish)
-	embeds := formatAccounts(p, title, accounts)
 
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: embeds,
-			Flags:  discordgo.MessageFlagsEphemeral,
-		},
-	})
+	length := lb.getSeasonLength()
+	if length == SEASON_CUSTOM {
+		length = p.Sprintf("%d days", lb.SeasonDays)
+	}
+	return p.Sprintf("%s, rolling over at %02d:00 %s", length, lb.RolloverHour, lb.getLocation())
 }
 
 // rank returns the rank of the member in the leaderboard.
@@ -199,20 +353,89 @@ func rank(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	account := bank.GetAccount(i.GuildID, i.Member.User.ID)
 	lb := getLeaderboard(i.GuildID)
-	currentRank := getCurrentRanking(lb, account)
-	monthlyRank := getMonthlyRanking(lb, account)
-	lifetimeRank := getLifetimeRanking(lb, account)
 
 	p := discmsg.GetPrinter(language.AmericanEnglish)
-	resp := p.Sprintf("**Current Rank**: %d\n**Monthly Rank**: %d\n**Lifetime Rank**: %d\n", currentRank, monthlyRank, lifetimeRank)
+	var sb strings.Builder
+	for _, metric := range balanceMetrics {
+		rank := lb.getPosition(metric, account)
+		yesterday := getPreviousSnapshot(lb, metric).getRank(account.MemberID)
+		seasonStart := getSeasonStartSnapshot(lb, metric).getRank(account.MemberID)
+		sb.WriteString(p.Sprintf("**%s Rank**: %d (%s since yesterday, %s since the season started)\n",
+			cases.Title(language.AmericanEnglish).String(metric.Name),
+			rank,
+			formatMovement(yesterday, rank),
+			formatMovement(seasonStart, rank),
+		))
+	}
+	resp := sb.String()
+	archive, standing := getBestSeason(i.GuildID, i.Member.User.ID)
+	if archive != nil {
+		resp += p.Sprintf("**Best Season**: #%d in season %d (%s) with %d\n", standing.Rank, archive.Season, archive.Title, standing.Balance)
+	}
 	discmsg.SendEphemeralResponse(s, i, resp)
 }
 
-// formatAccounts formats the leaderboard to be sent to a Discord server
-func formatAccounts(p *message.Printer, title string, accounts []*bank.Account) []*discordgo.MessageEmbed {
-	log.Trace("--> leaderboard.formatAccounts")
-	defer log.Trace("<-- leaderboard.formatAccounts")
+// seasonHistory returns the final standings for a past season, or a list of the most recent seasons
+// if no season is provided.
+func seasonHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.seasonHistory")
+	defer log.Trace("<-- leaderboard.seasonHistory")
 
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	options := i.ApplicationCommandData().Options[0].Options
+	if len(options) == 0 {
+		archives := getSeasonArchives(i.GuildID, SEASON_HISTORY_SIZE)
+		if len(archives) == 0 {
+			discmsg.SendEphemeralResponse(s, i, "No seasons have been completed yet.")
+			return
+		}
+		var sb strings.Builder
+		for _, archive := range archives {
+			winner := "no winner"
+			if top := archive.getTop(1); len(top) != 0 {
+				winner = p.Sprintf("won by %s with %d", top[0].Name, top[0].Balance)
+			}
+			sb.WriteString(p.Sprintf("**Season %d**: %s, %s\n", archive.Season, archive.Title, winner))
+		}
+		sb.WriteString("\nUse `/lb history season:<number>` to see the final standings for a season.")
+		discmsg.SendEphemeralResponse(s, i, sb.String())
+		return
+	}
+
+	season := int(options[0].IntValue())
+	archive, err := getSeasonArchive(i.GuildID, season)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Season %d was not found.", season))
+		return
+	}
+
+	title := p.Sprintf("Season %d: %s", archive.Season, archive.Title)
+	embeds := formatStandings(p, title, archive.getTop(10))
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: embeds,
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// formatStandings formats the final standings for a season to be sent to a Discord server
+func formatStandings(p *message.Printer, title string, standings []*SeasonStanding) []*discordgo.MessageEmbed {
+	log.Trace("--> leaderboard.formatStandings")
+	defer log.Trace("<-- leaderboard.formatStandings")
+
+	rows := make([][]string, 0, len(standings))
+	for _, standing := range standings {
+		rows = append(rows, []string{strconv.Itoa(standing.Rank), standing.Name, p.Sprintf("%d", standing.Balance)})
+	}
+
+	return formatTable(p, title, rows)
+}
+
+// formatTable formats the rank, name and balance for each row into an embed sent to a Discord server
+func formatTable(p *message.Printer, title string, rows [][]string) []*discordgo.MessageEmbed {
 	var tableBuffer strings.Builder
 	table := tablewriter.NewWriter(&tableBuffer)
 	table.SetAutoWrapText(false)
@@ -227,25 +450,9 @@ func formatAccounts(p *message.Printer, title string, accounts []*bank.Account)
 	table.SetTablePadding("\t")
 	table.SetNoWhiteSpace(true)
 	table.SetHeader([]string{"#", "Name", "Balance"})
-
-	// A bit of a hack, but good enough....
-	for i, account := range accounts {
-		member := guild.GetMember(accounts[0].GuildID, account.MemberID)
-		var balance int
-		switch title {
-		case "Current Leaderboard":
-			balance = account.CurrentBalance
-		case "Monthly Leaderboard":
-			balance = account.MonthlyBalance
-		case "Lifetime Leaderboard":
-			balance = account.LifetimeBalance
-		default:
-			balance = account.CurrentBalance
-		}
-		data := []string{strconv.Itoa(i + 1), member.Name, p.Sprintf("%d", balance)}
-		table.Append(data)
-	}
+	table.AppendBulk(rows)
 	table.Render()
+
 	embeds := []*discordgo.MessageEmbed{
 		{
 			Type:  discordgo.EmbedTypeRich,
@@ -1,12 +1,17 @@
 package leaderboard
 
 import (
+	"fmt"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 const (
-	LEADERBOARD_COLLECTION = "leaderboards"
+	LEADERBOARD_COLLECTION    = "leaderboards"
+	SEASON_ARCHIVE_COLLECTION = "leaderboard_seasons"
+	RANK_SNAPSHOT_COLLECTION  = "leaderboard_snapshots"
 )
 
 // readLeaderboard reads the leaderboard from the database and returns the value, if it exists, or returns nil if the
@@ -48,3 +53,129 @@ func writeLeaderboard(lb *Leaderboard) error {
 
 	return nil
 }
+
+// readSeasonArchive reads the archive for the given season number from the database.
+func readSeasonArchive(guildID string, season int) (*SeasonArchive, error) {
+	log.Trace("--> leaderboard.readSeasonArchive")
+	defer log.Trace("<-- leaderboard.readSeasonArchive")
+
+	filter := bson.M{"guild_id": guildID, "season": season}
+	var archive SeasonArchive
+	err := db.FindOne(SEASON_ARCHIVE_COLLECTION, filter, &archive)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "season": season}).Debug("season archive not found in the database")
+		return nil, fmt.Errorf("%w: %d", ErrSeasonNotFound, season)
+	}
+
+	return &archive, nil
+}
+
+// readSeasonArchives reads the most recent season archives for the guild from the database.
+func readSeasonArchives(guildID string, limit int) []*SeasonArchive {
+	log.Trace("--> leaderboard.readSeasonArchives")
+	defer log.Trace("<-- leaderboard.readSeasonArchives")
+
+	filter := bson.M{"guild_id": guildID}
+	sort := bson.D{{Key: "season", Value: -1}}
+	var archives []*SeasonArchive
+	err := db.FindMany(SEASON_ARCHIVE_COLLECTION, filter, &archives, sort, int64(limit))
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "error": err}).Error("unable to read season archives from the database")
+		return nil
+	}
+
+	return archives
+}
+
+// readMemberSeasonArchives reads all the season archives in which the member placed from the database.
+func readMemberSeasonArchives(guildID string, memberID string) []*SeasonArchive {
+	log.Trace("--> leaderboard.readMemberSeasonArchives")
+	defer log.Trace("<-- leaderboard.readMemberSeasonArchives")
+
+	filter := bson.M{"guild_id": guildID, "standings.member_id": memberID}
+	sort := bson.D{{Key: "season", Value: 1}}
+	var archives []*SeasonArchive
+	err := db.FindMany(SEASON_ARCHIVE_COLLECTION, filter, &archives, sort, 0)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "member": memberID, "error": err}).Error("unable to read season archives for the member from the database")
+		return nil
+	}
+
+	return archives
+}
+
+// countSeasonArchives returns the number of seasons archived for the guild.
+func countSeasonArchives(guildID string) int {
+	log.Trace("--> leaderboard.countSeasonArchives")
+	defer log.Trace("<-- leaderboard.countSeasonArchives")
+
+	filter := bson.M{"guild_id": guildID}
+	count, _ := db.Count(SEASON_ARCHIVE_COLLECTION, filter)
+	return count
+}
+
+// writeSeasonArchive saves the season archive to the database.
+func writeSeasonArchive(archive *SeasonArchive) error {
+	log.Trace("--> leaderboard.writeSeasonArchive")
+	defer log.Trace("<-- leaderboard.writeSeasonArchive")
+
+	filter := bson.M{"guild_id": archive.GuildID, "season": archive.Season}
+	err := db.UpdateOrInsert(SEASON_ARCHIVE_COLLECTION, filter, archive)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": archive.GuildID, "season": archive.Season, "error": err}).Error("unable to save season archive to the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": archive.GuildID, "season": archive.Season}).Debug("save season archive to the database")
+
+	return nil
+}
+
+// readRankSnapshot reads the most recent snapshot of the ranks for the metric taken before the given
+// time from the database, or returns nil if there isn't one.
+func readRankSnapshot(guildID string, metric string, before time.Time) *RankSnapshot {
+	log.Trace("--> leaderboard.readRankSnapshot")
+	defer log.Trace("<-- leaderboard.readRankSnapshot")
+
+	filter := bson.M{"guild_id": guildID, "metric": metric, "day": bson.M{"$lt": before}}
+	sort := bson.D{{Key: "day", Value: -1}}
+	var snapshots []*RankSnapshot
+	err := db.FindMany(RANK_SNAPSHOT_COLLECTION, filter, &snapshots, sort, 1)
+	if err != nil || len(snapshots) == 0 {
+		log.WithFields(log.Fields{"guild": guildID, "metric": metric, "before": before}).Debug("rank snapshot not found in the database")
+		return nil
+	}
+
+	return snapshots[0]
+}
+
+// writeRankSnapshot saves the snapshot of the ranks for a metric to the database.
+func writeRankSnapshot(snapshot *RankSnapshot) error {
+	log.Trace("--> leaderboard.writeRankSnapshot")
+	defer log.Trace("<-- leaderboard.writeRankSnapshot")
+
+	filter := bson.M{"guild_id": snapshot.GuildID, "metric": snapshot.Metric, "day": snapshot.Day}
+	err := db.UpdateOrInsert(RANK_SNAPSHOT_COLLECTION, filter, snapshot)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": snapshot.GuildID, "metric": snapshot.Metric, "error": err}).Error("unable to save rank snapshot to the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": snapshot.GuildID, "metric": snapshot.Metric, "day": snapshot.Day}).Debug("save rank snapshot to the database")
+
+	return nil
+}
+
+// deleteRankSnapshots removes the snapshots of the ranks taken before the given time from the database.
+func deleteRankSnapshots(guildID string, before time.Time) error {
+	log.Trace("--> leaderboard.deleteRankSnapshots")
+	defer log.Trace("<-- leaderboard.deleteRankSnapshots")
+
+	filter := bson.M{"guild_id": guildID, "day": bson.M{"$lt": before}}
+	err := db.DeleteMany(RANK_SNAPSHOT_COLLECTION, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": guildID, "before": before, "error": err}).Error("unable to delete rank snapshots from the database")
+		return err
+	}
+	log.WithFields(log.Fields{"guild": guildID, "before": before}).Debug("delete rank snapshots from the database")
+
+	return nil
+}
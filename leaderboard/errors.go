@@ -3,5 +3,14 @@ package leaderboard
 import "errors"
 
 var (
+	ErrEmptyReward             = errors.New("a reward must include credits, a role, or both")
+	ErrInvalidRewardAmount     = errors.New("the reward amount can't be negative")
+	ErrInvalidRewardRank       = errors.New("rewards can only be given to the top 10 finishers")
+	ErrInvalidRolloverHour     = errors.New("the rollover hour must be between 0 and 23")
+	ErrInvalidSeasonDays       = errors.New("a custom season must be between 1 and 365 days long")
+	ErrInvalidSeasonLength     = errors.New("the season length must be weekly, monthly or custom")
+	ErrInvalidTimezone         = errors.New("the timezone is not a valid IANA timezone, such as America/New_York")
+	ErrRewardNotFound          = errors.New("no reward is set for that rank")
+	ErrSeasonNotFound          = errors.New("season not found")
 	ErrUnableToSaveLeaderboard = errors.New("unable to save leaderboard to the database")
 )
@@ -7,20 +7,27 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
 	"github.com/rbrabson/goblin/internal/discmsg"
-	"github.com/rbrabson/goblin/internal/disctime"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/text/language"
 )
 
-// A Leaderboard is used to send a monthly leaderboard to the Discord server for each guild.
+// A Leaderboard is used to send the leaderboard to the Discord server for each guild at the end of
+// each season.
 type Leaderboard struct {
-	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID    string             `json:"guild_id" bson:"guild_id"`
-	ChannelID  string             `json:"channel_id" bson:"channel_id"`
-	LastSeason time.Time          `json:"last_season" bson:"last_season"`
+	ID            primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID       string             `json:"guild_id" bson:"guild_id"`
+	ChannelID     string             `json:"channel_id" bson:"channel_id"`
+	LastSeason    time.Time          `json:"last_season" bson:"last_season"`                         // Start of the current season
+	SeasonLength  string             `json:"season_length,omitempty" bson:"season_length,omitempty"` // weekly, monthly or custom
+	SeasonDays    int                `json:"season_days,omitempty" bson:"season_days,omitempty"`     // Number of days in a custom season
+	Timezone      string             `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	RolloverHour  int                `json:"rollover_hour" bson:"rollover_hour"`
+	Rewards       []*SeasonReward    `json:"rewards,omitempty" bson:"rewards,omitempty"`
+	RewardedRoles []*RewardedRole    `json:"rewarded_roles,omitempty" bson:"rewarded_roles,omitempty"` // Roles to remove when the season ends
 }
 
 func newLeaderboard(guildID string) *Leaderboard {
@@ -28,10 +35,14 @@ func newLeaderboard(guildID string) *Leaderboard {
 	defer log.Trace("<-- leaderboard.newLeaderboard")
 
 	lb := &Leaderboard{
-		GuildID:    guildID,
-		LastSeason: disctime.CurrentMonth(time.Now()),
+		GuildID:      guildID,
+		SeasonLength: DEFAULT_SEASON_LENGTH,
+		Timezone:     DEFAULT_TIMEZONE,
 	}
+	lb.LastSeason = lb.seasonStart(time.Now())
 	writeLeaderboard(lb)
+	scheduleSeason(lb)
+	scheduleSnapshots(lb)
 	log.WithFields(log.Fields{"guildID": guildID, "leaderboard": lb}).Trace("new leaderboard")
 
 	return lb
@@ -166,75 +177,53 @@ func getLifetimeRanking(lb *Leaderboard, account *bank.Account) int {
 	return rank
 }
 
-// sendMonthlyLeaderboard publishes the monthly leaderboard to the bank channel.
-func sendhMonthlyLeaderboard(lb *Leaderboard) error {
-	log.Trace("--> bank.sendMonthlyLeaderboard")
-	defer log.Trace("<-- bank.sendMonthlyLeaderboard")
+// sendSeasonLeaderboard publishes the leaderboard for the season that just ended to the leaderboard channel.
+func sendSeasonLeaderboard(lb *Leaderboard) error {
+	log.Trace("--> leaderboard.sendSeasonLeaderboard")
+	defer log.Trace("<-- leaderboard.sendSeasonLeaderboard")
 
-	// Get the top 10 accounts for this month
+	// Get the top 10 accounts for this season
 	sortedAccounts := lb.getMonthlyLeaderboard()
 	leaderboardSize := min(10, len(sortedAccounts))
 	sortedAccounts = sortedAccounts[:leaderboardSize]
 
-	firstOfMonth := disctime.PreviousMonth(time.Now())
-	year, month, _ := firstOfMonth.Date()
 	if lb.ChannelID != "" {
 		p := discmsg.GetPrinter(language.AmericanEnglish)
-		embeds := formatAccounts(p, fmt.Sprintf("%s %d Top 10", month, year), sortedAccounts)
+		previous := getPreviousSnapshot(lb, getBalanceMetric("monthly"))
+		cardRows := make([]*cardRow, 0, len(sortedAccounts))
+		rows := make([][]string, 0, len(sortedAccounts))
+		for idx, account := range sortedAccounts {
+			member := guild.GetMember(lb.GuildID, account.MemberID)
+			row := newCardRow(p, member, account.MonthlyBalance, idx+1, previous, "")
+			cardRows = append(cardRows, row)
+			rows = append(rows, row.tableRow())
+		}
+		embeds := formatTable(p, lb.seasonTitle(lb.LastSeason), rows)
+		files := attachCard(embeds[0], cardRows)
 		_, err := bot.Session.ChannelMessageSendComplex(lb.ChannelID, &discordgo.MessageSend{
 			Embeds: embeds,
+			Files:  files,
 		})
 		if err != nil {
-			log.Error("unable to send montly leaderboard, err:", err)
+			log.Error("unable to send season leaderboard, err:", err)
 			return err
 		}
 	} else {
-		log.WithField("guildID", lb.ChannelID).Warning("no leaderboard channel set for server")
+		log.WithField("guildID", lb.GuildID).Warning("no leaderboard channel set for server")
 	}
 	return nil
 }
 
-// publishMonthlyLeaderboard sends the monthly leaderboard to each guild.
-func sendMonthlyLeaderboard() {
-	log.Trace("--> bank.sendMonthlyLeaderboard")
-	defer log.Trace("<-- bank.sendMonthlyLeaderboard")
-
-	// Get the last season for the banks, defaulting to the current time if there are no banks.
-	// This handles the off-chance that the server crashed and a new month starts before the
-	// server is restarted.
-	lastSeason := time.Now()
-	leaderboards := getLeaderboards()
-	for _, lb := range leaderboards {
-		lastSeason = lb.LastSeason
-		break
-	}
-
-	for {
-		nextMonth := disctime.NextMonth(lastSeason)
-		time.Sleep(time.Until(nextMonth))
-		lastSeason = nextMonth
-
-		leaderboards := getLeaderboards()
-		for _, lb := range leaderboards {
-			err := sendhMonthlyLeaderboard(lb)
-			if err != nil {
-				log.WithFields(log.Fields{"guildID": lb.GuildID, "error": err}).Error("unable to send monthly leaderboard")
-			} else {
-				lb.LastSeason = lastSeason
-				writeLeaderboard(lb)
-			}
-		}
-
-		bank.ResetMonthlyBalances()
-	}
-}
-
 // String returns a string representation of the Leaderboard.
 func (lb *Leaderboard) String() string {
-	return fmt.Sprintf("Leaderboard{ID=%s, GuildID=%s, ChannelID=%s, LastSeason=%s}",
+	return fmt.Sprintf("Leaderboard{ID=%s, GuildID=%s, ChannelID=%s, LastSeason=%s, SeasonLength=%s, SeasonDays=%d, Timezone=%s, RolloverHour=%d}",
 		lb.ID.Hex(),
 		lb.GuildID,
 		lb.ChannelID,
 		lb.LastSeason,
+		lb.SeasonLength,
+		lb.SeasonDays,
+		lb.Timezone,
+		lb.RolloverHour,
 	)
 }
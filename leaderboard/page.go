@@ -0,0 +1,288 @@
+package leaderboard
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	LEADERBOARD_PAGE_SIZE = 10
+)
+
+// balanceMetric is a bank account balance that members may be ranked by on a leaderboard.
+type balanceMetric struct {
+	Name  string                  // Name of the `/lb` subcommand
+	Title string                  // Title shown for the leaderboard
+	Field string                  // Database field for the balance
+	Value func(*bank.Account) int // Returns the balance for an account
+}
+
+var (
+	balanceMetrics = []*balanceMetric{
+		{
+			Name:  "current",
+			Title: "Current Leaderboard",
+			Field: "current_balance",
+			Value: func(a *bank.Account) int { return a.CurrentBalance },
+		},
+		{
+			Name:  "monthly",
+			Title: "Monthly Leaderboard",
+			Field: "monthly_balance",
+			Value: func(a *bank.Account) int { return a.MonthlyBalance },
+		},
+		{
+			Name:  "lifetime",
+			Title: "Lifetime Leaderboard",
+			Field: "lifetime_balance",
+			Value: func(a *bank.Account) int { return a.LifetimeBalance },
+		},
+	}
+)
+
+// getBalanceMetric returns the balance metric with the given name, or `nil` if one doesn't exist.
+func getBalanceMetric(name string) *balanceMetric {
+	for _, metric := range balanceMetrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	return nil
+}
+
+// getLeaderboardPage returns a page of the accounts ranked by the balance metric.
+func (lb *Leaderboard) getLeaderboardPage(metric *balanceMetric, skip int, limit int) []*bank.Account {
+	log.Trace("--> leaderboard.getLeaderboardPage")
+	defer log.Trace("<-- leaderboard.getLeaderboardPage")
+
+	filter := bson.D{{Key: "guild_id", Value: lb.GuildID}}
+	sort := bson.D{{Key: metric.Field, Value: -1}, {Key: "_id", Value: 1}}
+
+	return bank.GetAccountPage(lb.GuildID, filter, sort, int64(skip), int64(limit))
+}
+
+// countAccounts returns the number of accounts ranked on the leaderboard.
+func (lb *Leaderboard) countAccounts() int {
+	log.Trace("--> leaderboard.countAccounts")
+	defer log.Trace("<-- leaderboard.countAccounts")
+
+	filter := bson.D{{Key: "guild_id", Value: lb.GuildID}}
+	count, _ := db.Count(bank.ACCOUNT_COLLECTION, filter)
+	return count
+}
+
+// getPosition returns the position of the account on the leaderboard for the balance metric. The
+// accounts are ordered the same way as the pages of the leaderboard, so accounts with the same balance
+// have different positions and the account is always found on the page for its position.
+func (lb *Leaderboard) getPosition(metric *balanceMetric, account *bank.Account) int {
+	log.Trace("--> leaderboard.getPosition")
+	defer log.Trace("<-- leaderboard.getPosition")
+
+	if account.ID.IsZero() {
+		// A new account doesn't have an ID until it is read back from the database
+		account = bank.GetAccount(lb.GuildID, account.MemberID)
+	}
+
+	value := metric.Value(account)
+	filter := bson.D{
+		{Key: "guild_id", Value: lb.GuildID},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: metric.Field, Value: bson.D{{Key: "$gt", Value: value}}}},
+			bson.D{{Key: metric.Field, Value: value}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: account.ID}}}},
+		}},
+	}
+	count, _ := db.Count(bank.ACCOUNT_COLLECTION, filter)
+	return count + 1
+}
+
+// sendLeaderboardPage sends the first page of the leaderboard for the balance metric.
+func sendLeaderboardPage(s *discordgo.Session, i *discordgo.InteractionCreate, metric *balanceMetric) {
+	log.Trace("--> leaderboard.sendLeaderboardPage")
+	defer log.Trace("<-- leaderboard.sendLeaderboardPage")
+
+	lb := getLeaderboard(i.GuildID)
+	embeds, components, files := getLeaderboardPage(lb, metric, i.Member.User.ID, 1)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: components,
+			Files:      files,
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to send the leaderboard")
+	}
+}
+
+// leaderboardPrevious shows the previous page of the leaderboard.
+func leaderboardPrevious(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.leaderboardPrevious")
+	defer log.Trace("<-- leaderboard.leaderboardPrevious")
+
+	changeLeaderboardPage(s, i, func(page int, position int) int { return page - 1 })
+}
+
+// leaderboardNext shows the next page of the leaderboard.
+func leaderboardNext(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.leaderboardNext")
+	defer log.Trace("<-- leaderboard.leaderboardNext")
+
+	changeLeaderboardPage(s, i, func(page int, position int) int { return page + 1 })
+}
+
+// leaderboardJumpToMe shows the page of the leaderboard that includes the member.
+func leaderboardJumpToMe(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> leaderboard.leaderboardJumpToMe")
+	defer log.Trace("<-- leaderboard.leaderboardJumpToMe")
+
+	changeLeaderboardPage(s, i, func(page int, position int) int { return (position-1)/LEADERBOARD_PAGE_SIZE + 1 })
+}
+
+// changeLeaderboardPage updates the leaderboard message to show a different page. The balance metric
+// and the current page are encoded in the custom ID of the button that was pressed, and `nextPage`
+// returns the page to show given the current page and the member's position on the leaderboard.
+func changeLeaderboardPage(s *discordgo.Session, i *discordgo.InteractionCreate, nextPage func(page int, position int) int) {
+	log.Trace("--> leaderboard.changeLeaderboardPage")
+	defer log.Trace("<-- leaderboard.changeLeaderboardPage")
+
+	args := discord.ComponentArgs(i)
+	if len(args) != 2 {
+		discmsg.SendEphemeralResponse(s, i, "Unable to find the leaderboard")
+		return
+	}
+	metric := getBalanceMetric(args[0])
+	page, err := strconv.Atoi(args[1])
+	if metric == nil || err != nil {
+		discmsg.SendEphemeralResponse(s, i, "Unable to find the leaderboard")
+		return
+	}
+
+	lb := getLeaderboard(i.GuildID)
+	account := bank.GetAccount(i.GuildID, i.Member.User.ID)
+	position := lb.getPosition(metric, account)
+
+	embeds, components, files := getLeaderboardPage(lb, metric, i.Member.User.ID, nextPage(page, position))
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:      embeds,
+			Components:  components,
+			Files:       files,
+			Attachments: &[]*discordgo.MessageAttachment{}, // Replace the card for the previous page
+		},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"guild": i.GuildID, "error": err}).Error("unable to update the leaderboard")
+	}
+}
+
+// getLeaderboardPage returns the embeds, buttons and rendered card used to show a page of the
+// leaderboard. The member's row is highlighted and, if the member isn't on the page, shown below
+// the page. If the page is out of range, the nearest valid page is returned.
+func getLeaderboardPage(lb *Leaderboard, metric *balanceMetric, memberID string, page int) ([]*discordgo.MessageEmbed, []discordgo.MessageComponent, []*discordgo.File) {
+	log.Trace("--> leaderboard.getLeaderboardPage")
+	defer log.Trace("<-- leaderboard.getLeaderboardPage")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	count := lb.countAccounts()
+	pages := max((count+LEADERBOARD_PAGE_SIZE-1)/LEADERBOARD_PAGE_SIZE, 1)
+	page = min(max(page, 1), pages)
+
+	skip := (page - 1) * LEADERBOARD_PAGE_SIZE
+	accounts := lb.getLeaderboardPage(metric, skip, LEADERBOARD_PAGE_SIZE)
+
+	previous := getPreviousSnapshot(lb, metric)
+	cardRows := make([]*cardRow, 0, len(accounts)+2)
+	for idx, account := range accounts {
+		member := guild.GetMember(lb.GuildID, account.MemberID)
+		cardRows = append(cardRows, newCardRow(p, member, metric.Value(account), skip+idx+1, previous, memberID))
+	}
+	onPage := slices.ContainsFunc(cardRows, func(row *cardRow) bool { return row.Highlight })
+	if !onPage {
+		account := bank.GetAccount(lb.GuildID, memberID)
+		member := guild.GetMember(lb.GuildID, memberID)
+		position := lb.getPosition(metric, account)
+		cardRows = append(cardRows, &cardRow{})
+		cardRows = append(cardRows, newCardRow(p, member, metric.Value(account), position, previous, memberID))
+	}
+
+	rows := make([][]string, 0, len(cardRows))
+	for _, row := range cardRows {
+		rows = append(rows, row.tableRow())
+	}
+
+	embeds := formatTable(p, metric.Title, rows)
+	files := attachCard(embeds[0], cardRows)
+	embeds[0].Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", page, pages),
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Previous",
+				Style:    discordgo.SecondaryButton,
+				Disabled: page <= 1,
+				CustomID: discord.CustomID("lb_previous", metric.Name, strconv.Itoa(page)),
+			},
+			discordgo.Button{
+				Label:    "Next",
+				Style:    discordgo.SecondaryButton,
+				Disabled: page >= pages,
+				CustomID: discord.CustomID("lb_next", metric.Name, strconv.Itoa(page)),
+			},
+			discordgo.Button{
+				Label:    "Jump to Me",
+				Style:    discordgo.PrimaryButton,
+				Disabled: onPage,
+				CustomID: discord.CustomID("lb_me", metric.Name, strconv.Itoa(page)),
+			},
+		}},
+	}
+
+	return embeds, components, files
+}
+
+// newCardRow returns the row for a member ranked on the leaderboard, including how far the member has
+// moved since the previous snapshot.
+func newCardRow(p *message.Printer, member *guild.Member, balance int, rank int, previous *RankSnapshot, memberID string) *cardRow {
+	row := &cardRow{
+		Rank:      rank,
+		Name:      member.Name,
+		Balance:   p.Sprintf("%d", balance),
+		Highlight: member.MemberID == memberID,
+	}
+	if previousRank := previous.getRank(member.MemberID); previousRank != 0 {
+		row.Movement = previousRank - rank
+	}
+	return row
+}
+
+// tableRow returns the row formatted for the text version of the leaderboard.
+func (row *cardRow) tableRow() []string {
+	if row.Rank == 0 {
+		return []string{"...", "", ""}
+	}
+	rank := strconv.Itoa(row.Rank)
+	if row.Highlight {
+		rank = "▶" + rank
+	}
+	balance := row.Balance
+	if row.Movement != 0 {
+		balance += " " + formatMovement(row.Rank+row.Movement, row.Rank)
+	}
+	return []string{rank, row.Name, balance}
+}
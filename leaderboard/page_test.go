@@ -0,0 +1,51 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/rbrabson/goblin/bank"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetBalanceMetric(t *testing.T) {
+	account := &bank.Account{CurrentBalance: 1, MonthlyBalance: 2, LifetimeBalance: 3}
+	for expected, name := range []string{"current", "monthly", "lifetime"} {
+		metric := getBalanceMetric(name)
+		if metric == nil {
+			t.Fatalf("expected a metric for %s", name)
+		}
+		if value := metric.Value(account); value != expected+1 {
+			t.Errorf("expected %d for %s, got %d", expected+1, name, value)
+		}
+	}
+	if metric := getBalanceMetric("unknown"); metric != nil {
+		t.Errorf("expected no metric, got %s", metric.Name)
+	}
+}
+
+func TestGetPosition(t *testing.T) {
+	defer func() {
+		db.Delete(LEADERBOARD_COLLECTION, bson.M{"guild_id": "12345"})
+		db.Delete(bank.BANK_COLLECTION, bson.M{"guild_id": "12345"})
+		db.DeleteMany(bank.ACCOUNT_COLLECTION, bson.M{"guild_id": "12345"})
+	}()
+
+	// All the accounts have the same default balance, so they are only ordered by their IDs
+	bank.SetDB(db)
+	bank.GetBank("12345")
+	for _, memberID := range []string{"1", "2", "3"} {
+		bank.GetAccount("12345", memberID)
+	}
+
+	lb := newLeaderboard("12345")
+	metric := getBalanceMetric("current")
+	accounts := lb.getLeaderboardPage(metric, 0, LEADERBOARD_PAGE_SIZE)
+	if len(accounts) != 3 {
+		t.Fatalf("expected 3 accounts, got %d", len(accounts))
+	}
+	for idx, account := range accounts {
+		if position := lb.getPosition(metric, account); position != idx+1 {
+			t.Errorf("expected member %s to be at position %d, got %d", account.MemberID, idx+1, position)
+		}
+	}
+}
@@ -2,11 +2,12 @@ package leaderboard
 
 import (
 	"fmt"
-	"slices"
+	"maps"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
 	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -34,25 +35,36 @@ func Start() {
 func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 	bot = b
 	db = d
-	go sendMonthlyLeaderboard()
+	b.Session.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
+		scheduleSeasons()
+	})
 }
 
 // GetCommands returns the commands for the banking system
 func (plugin *Plugin) GetCommands() []*discordgo.ApplicationCommand {
-	commands := make([]*discordgo.ApplicationCommand, 0, len(adminCommands)+len(memberCommands))
-	commands = append(commands, adminCommands...)
-	commands = append(commands, memberCommands...)
+	commands := make([]*discordgo.ApplicationCommand, 0, 2)
+	commands = append(commands, adminRouter.Commands()...)
+	commands = append(commands, memberRouter.Commands()...)
 	return commands
 }
 
+// GetCommandPermissions returns the permission required to use each of the commands for the leaderboard
+func (plugin *Plugin) GetCommandPermissions() map[string]guild.Permission {
+	permissions := memberRouter.Permissions(guild.PermissionMember)
+	maps.Copy(permissions, adminRouter.Permissions(guild.PermissionAdmin))
+	return permissions
+}
+
 // GetCommandHandlers returns the command handlers for the banking system
 func (plugin *Plugin) GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	handlers := memberRouter.Handlers()
+	maps.Copy(handlers, adminRouter.Handlers())
+	return handlers
 }
 
 // GetComponentHandlers returns the component handlers for the banking system
 func (plugin *Plugin) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	return componentHandlers
 }
 
 // GetName returns the name of the banking system plugin
@@ -62,32 +74,12 @@ func (plugin *Plugin) GetName() string {
 
 // GetHelp returns the member help for the banking system
 func (plugin *Plugin) GetHelp() []string {
-	help := make([]string, 0, 1)
-
-	commandPrefix := memberCommands[0].Name
-	for _, command := range memberCommands[0].Options {
-		commandDescription := fmt.Sprintf("- **/%s %s**:  %s\n", commandPrefix, command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, memberRouter.Help()...)
 }
 
 // GetAdminHelp returns the admin help for the banking system
 func (plugin *Plugin) GetAdminHelp() []string {
-	help := make([]string, 0, len(adminCommands[0].Options))
-
-	commandPrefix := adminCommands[0].Name
-	for _, command := range adminCommands[0].Options {
-		commandDescription := fmt.Sprintf("- **/%s %s**:  %s\n", commandPrefix, command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, adminRouter.Help()...)
 }
@@ -0,0 +1,167 @@
+package leaderboard
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/internal/discmsg"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+)
+
+const (
+	MAX_REWARD_RANK = 10 // Rewards can be given to, at most, the top 10 members of a season
+)
+
+// SeasonReward is the reward given to the member who finishes a season at the given rank.
+type SeasonReward struct {
+	Rank   int    `json:"rank" bson:"rank"`
+	Amount int    `json:"amount" bson:"amount"`
+	RoleID string `json:"role_id,omitempty" bson:"role_id,omitempty"` // Role held until the end of the next season
+}
+
+// RewardedRole is a role given to a member as a season reward, which is removed when the next season ends.
+type RewardedRole struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	RoleID   string `json:"role_id" bson:"role_id"`
+}
+
+// getReward returns the reward for the given rank, or `nil` if there isn't one.
+func (lb *Leaderboard) getReward(rank int) *SeasonReward {
+	for _, reward := range lb.Rewards {
+		if reward.Rank == rank {
+			return reward
+		}
+	}
+	return nil
+}
+
+// setReward sets the reward given to the member finishing a season at the given rank.
+func (lb *Leaderboard) setReward(rank int, amount int, roleID string) error {
+	log.Trace("--> leaderboard.setReward")
+	defer log.Trace("<-- leaderboard.setReward")
+
+	if rank < 1 || rank > MAX_REWARD_RANK {
+		return ErrInvalidRewardRank
+	}
+	if amount < 0 {
+		return ErrInvalidRewardAmount
+	}
+	if amount == 0 && roleID == "" {
+		return ErrEmptyReward
+	}
+
+	reward := lb.getReward(rank)
+	if reward == nil {
+		reward = &SeasonReward{Rank: rank}
+		lb.Rewards = append(lb.Rewards, reward)
+		slices.SortFunc(lb.Rewards, func(a, b *SeasonReward) int {
+			return a.Rank - b.Rank
+		})
+	}
+	reward.Amount = amount
+	reward.RoleID = roleID
+
+	return writeLeaderboard(lb)
+}
+
+// removeReward removes the reward for the given rank.
+func (lb *Leaderboard) removeReward(rank int) error {
+	log.Trace("--> leaderboard.removeReward")
+	defer log.Trace("<-- leaderboard.removeReward")
+
+	idx := slices.IndexFunc(lb.Rewards, func(reward *SeasonReward) bool {
+		return reward.Rank == rank
+	})
+	if idx == -1 {
+		return ErrRewardNotFound
+	}
+	lb.Rewards = slices.Delete(lb.Rewards, idx, idx+1)
+
+	return writeLeaderboard(lb)
+}
+
+// rewardSeason takes back the roles given for the previous season, then deposits the rewards and gives
+// the roles to the top finishers of the season that just ended. The champion is announced in the
+// leaderboard channel every season, along with any rewards that were given.
+func rewardSeason(s *discordgo.Session, lb *Leaderboard, archive *SeasonArchive) {
+	log.Trace("--> leaderboard.rewardSeason")
+	defer log.Trace("<-- leaderboard.rewardSeason")
+
+	for _, rewarded := range lb.RewardedRoles {
+		err := s.GuildMemberRoleRemove(lb.GuildID, rewarded.MemberID, rewarded.RoleID)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": lb.GuildID, "member": rewarded.MemberID, "role": rewarded.RoleID, "error": err}).Error("unable to remove season reward role")
+		}
+	}
+	lb.RewardedRoles = nil
+
+	if archive == nil {
+		return
+	}
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	var sb strings.Builder
+	for _, standing := range archive.getTop(MAX_REWARD_RANK) {
+		// The champion is announced whether or not there is a reward for first place
+		reward := lb.getReward(standing.Rank)
+		if reward == nil && standing.Rank != 1 {
+			continue
+		}
+
+		if standing.Rank == 1 {
+			sb.WriteString(p.Sprintf("🏆 <@%s> is the champion of %s", standing.MemberID, archive.Title))
+		} else {
+			sb.WriteString(p.Sprintf("#%d <@%s>", standing.Rank, standing.MemberID))
+		}
+		if reward != nil {
+			giveReward(s, lb, archive, standing, reward)
+			sb.WriteString(formatReward(reward))
+		}
+		sb.WriteString("\n")
+	}
+
+	if lb.ChannelID != "" && sb.Len() > 0 {
+		_, err := s.ChannelMessageSend(lb.ChannelID, sb.String())
+		if err != nil {
+			log.WithFields(log.Fields{"guild": lb.GuildID, "channel": lb.ChannelID, "error": err}).Error("unable to announce season rewards")
+		}
+	}
+}
+
+// giveReward deposits the credits and gives the role for the reward to a top finisher of the season.
+func giveReward(s *discordgo.Session, lb *Leaderboard, archive *SeasonArchive, standing *SeasonStanding, reward *SeasonReward) {
+	log.Trace("--> leaderboard.giveReward")
+	defer log.Trace("<-- leaderboard.giveReward")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+	if reward.Amount > 0 {
+		account := bank.GetAccount(lb.GuildID, standing.MemberID)
+		account.DepositWithReason(reward.Amount, p.Sprintf("season %d reward", archive.Season))
+	}
+	if reward.RoleID != "" {
+		err := s.GuildMemberRoleAdd(lb.GuildID, standing.MemberID, reward.RoleID)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": lb.GuildID, "member": standing.MemberID, "role": reward.RoleID, "error": err}).Error("unable to give season reward role")
+		} else {
+			lb.RewardedRoles = append(lb.RewardedRoles, &RewardedRole{MemberID: standing.MemberID, RoleID: reward.RoleID})
+		}
+	}
+	log.WithFields(log.Fields{"guild": lb.GuildID, "member": standing.MemberID, "rank": standing.Rank, "amount": reward.Amount, "role": reward.RoleID}).Info("gave season reward")
+}
+
+// formatReward returns a description of what is given for a season reward.
+func formatReward(reward *SeasonReward) string {
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	switch {
+	case reward.Amount > 0 && reward.RoleID != "":
+		return p.Sprintf(": %d credits and the <@&%s> role", reward.Amount, reward.RoleID)
+	case reward.RoleID != "":
+		return p.Sprintf(": the <@&%s> role", reward.RoleID)
+	default:
+		return p.Sprintf(": %d credits", reward.Amount)
+	}
+}
@@ -0,0 +1,38 @@
+package leaderboard
+
+import (
+	"testing"
+)
+
+func TestFormatReward(t *testing.T) {
+	tests := []struct {
+		reward   *SeasonReward
+		expected string
+	}{
+		{&SeasonReward{Rank: 1, Amount: 5000, RoleID: "42"}, ": 5,000 credits and the <@&42> role"},
+		{&SeasonReward{Rank: 2, RoleID: "42"}, ": the <@&42> role"},
+		{&SeasonReward{Rank: 3, Amount: 1000}, ": 1,000 credits"},
+	}
+
+	for _, tc := range tests {
+		if got := formatReward(tc.reward); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestGetReward(t *testing.T) {
+	lb := &Leaderboard{
+		Rewards: []*SeasonReward{
+			{Rank: 1, Amount: 5000},
+			{Rank: 3, Amount: 1000},
+		},
+	}
+
+	if reward := lb.getReward(3); reward == nil || reward.Amount != 1000 {
+		t.Errorf("expected a reward of 1000 for third place, got %v", reward)
+	}
+	if reward := lb.getReward(2); reward != nil {
+		t.Errorf("expected no reward for second place, got %v", reward)
+	}
+}
@@ -0,0 +1,264 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	SEASON_WEEKLY  = "weekly"
+	SEASON_MONTHLY = "monthly"
+	SEASON_CUSTOM  = "custom"
+)
+
+const (
+	DEFAULT_SEASON_LENGTH = SEASON_MONTHLY
+	DEFAULT_TIMEZONE      = "UTC"
+	MAX_SEASON_DAYS       = 365
+)
+
+var (
+	seasonTimers = make(map[string]*time.Timer)
+	seasonLock   = sync.Mutex{}
+)
+
+// getSeasonLength returns the type of season used by the leaderboard.
+func (lb *Leaderboard) getSeasonLength() string {
+	if lb.SeasonLength == "" {
+		return DEFAULT_SEASON_LENGTH
+	}
+	return lb.SeasonLength
+}
+
+// getLocation returns the timezone used to determine when a season rolls over.
+func (lb *Leaderboard) getLocation() *time.Location {
+	if lb.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(lb.Timezone)
+	if err != nil {
+		log.WithFields(log.Fields{"guild": lb.GuildID, "timezone": lb.Timezone, "error": err}).Warn("invalid leaderboard timezone, using UTC")
+		return time.UTC
+	}
+	return loc
+}
+
+// rolloverTime returns the time on the given day at which the season rolls over.
+func (lb *Leaderboard) rolloverTime(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, lb.RolloverHour, 0, 0, 0, lb.getLocation())
+}
+
+// seasonStart returns the start of the season in which `t` falls for a calendar-based season,
+// or the most recent rollover hour for all other seasons.
+func (lb *Leaderboard) seasonStart(t time.Time) time.Time {
+	t = t.In(lb.getLocation())
+	year, month, day := t.Date()
+
+	var start time.Time
+	switch lb.getSeasonLength() {
+	case SEASON_MONTHLY:
+		start = lb.rolloverTime(year, month, 1)
+		if start.After(t) {
+			start = lb.rolloverTime(year, month-1, 1)
+		}
+	default:
+		start = lb.rolloverTime(year, month, day)
+		if start.After(t) {
+			start = lb.rolloverTime(year, month, day-1)
+		}
+	}
+
+	return start
+}
+
+// nextSeason returns the time at which the season starting at `start` ends.
+func (lb *Leaderboard) nextSeason(start time.Time) time.Time {
+	start = start.In(lb.getLocation())
+	year, month, day := start.Date()
+
+	switch lb.getSeasonLength() {
+	case SEASON_WEEKLY:
+		return lb.rolloverTime(year, month, day+7)
+	case SEASON_CUSTOM:
+		return lb.rolloverTime(year, month, day+max(1, lb.SeasonDays))
+	default:
+		return lb.rolloverTime(year, month+1, 1)
+	}
+}
+
+// seasonTitle returns the title used for the leaderboard of the season starting at `start`.
+func (lb *Leaderboard) seasonTitle(start time.Time) string {
+	start = start.In(lb.getLocation())
+	switch lb.getSeasonLength() {
+	case SEASON_WEEKLY:
+		return fmt.Sprintf("Week of %s Top 10", start.Format("Jan 2, 2006"))
+	case SEASON_CUSTOM:
+		end := lb.nextSeason(start).AddDate(0, 0, -1)
+		return fmt.Sprintf("%s - %s Top 10", start.Format("Jan 2"), end.Format("Jan 2, 2006"))
+	default:
+		year, month, _ := start.Date()
+		return fmt.Sprintf("%s %d Top 10", month, year)
+	}
+}
+
+// setSeason sets the length of the season, along with the timezone and hour at which the season rolls
+// over. If the current season would already be over with the new settings, a new season is started.
+func (lb *Leaderboard) setSeason(length string, days int, timezone string, hour int) error {
+	log.Trace("--> leaderboard.setSeason")
+	defer log.Trace("<-- leaderboard.setSeason")
+
+	switch length {
+	case SEASON_WEEKLY, SEASON_MONTHLY:
+		days = 0
+	case SEASON_CUSTOM:
+		if days < 1 || days > MAX_SEASON_DAYS {
+			return ErrInvalidSeasonDays
+		}
+	default:
+		return ErrInvalidSeasonLength
+	}
+	if hour < 0 || hour > 23 {
+		return ErrInvalidRolloverHour
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+
+	lb.SeasonLength = length
+	lb.SeasonDays = days
+	lb.Timezone = timezone
+	lb.RolloverHour = hour
+
+	now := time.Now()
+	if lb.LastSeason.IsZero() || !lb.nextSeason(lb.LastSeason).After(now) {
+		lb.LastSeason = lb.seasonStart(now)
+	} else if lb.getSeasonLength() == SEASON_MONTHLY {
+		lb.LastSeason = lb.seasonStart(lb.LastSeason)
+	}
+	if err := writeLeaderboard(lb); err != nil {
+		return ErrUnableToSaveLeaderboard
+	}
+	scheduleSeason(lb)
+	scheduleSnapshots(lb)
+
+	return nil
+}
+
+// endSeason publishes the leaderboard for the season that just ended, gives out the season rewards,
+// resets the monthly balances for the guild and starts the next season.
+func endSeason(guildID string) {
+	log.Trace("--> leaderboard.endSeason")
+	defer log.Trace("<-- leaderboard.endSeason")
+
+	lb := readLeaderboard(guildID)
+	if lb == nil {
+		log.WithField("guild", guildID).Warn("leaderboard no longer exists, ending its season schedule")
+		return
+	}
+
+	now := time.Now()
+	end := lb.nextSeason(lb.LastSeason)
+	if end.After(now) {
+		// The season was changed since the timer was set
+		scheduleSeason(lb)
+		return
+	}
+
+	archive := archiveSeason(lb, end)
+	if guild.IsPluginEnabled(lb.GuildID, PLUGIN_NAME) {
+		err := sendSeasonLeaderboard(lb)
+		if err != nil {
+			log.WithFields(log.Fields{"guild": lb.GuildID, "error": err}).Error("unable to send season leaderboard")
+		}
+		rewardSeason(bot.Session, lb, archive)
+	} else {
+		log.WithField("guild", lb.GuildID).Info("leaderboard is disabled, not posting the season leaderboard")
+	}
+	bank.ResetGuildMonthlyBalances(lb.GuildID)
+
+	// Skip any seasons that ended while the bot was not running
+	for !lb.nextSeason(end).After(now) {
+		end = lb.nextSeason(end)
+	}
+	lb.LastSeason = end
+	writeLeaderboard(lb)
+	takeRankSnapshots(lb, lb.LastSeason)
+	log.WithFields(log.Fields{"guild": lb.GuildID, "season": lb.LastSeason}).Info("started new leaderboard season")
+
+	scheduleSeason(lb)
+}
+
+// scheduleSeason sets a timer for the end of the leaderboard's current season, replacing any timer
+// previously set for the guild.
+func scheduleSeason(lb *Leaderboard) {
+	log.Trace("--> leaderboard.scheduleSeason")
+	defer log.Trace("<-- leaderboard.scheduleSeason")
+
+	seasonLock.Lock()
+	defer seasonLock.Unlock()
+
+	if timer, ok := seasonTimers[lb.GuildID]; ok {
+		timer.Stop()
+	}
+	guildID := lb.GuildID
+	end := lb.nextSeason(lb.LastSeason)
+	seasonTimers[guildID] = time.AfterFunc(time.Until(end), func() {
+		endSeason(guildID)
+	})
+	log.WithFields(log.Fields{"guild": guildID, "end": end}).Debug("scheduled end of leaderboard season")
+}
+
+// scheduleSeasons sets the timers for the end of the current season, and for the daily rank snapshots,
+// for each guild with a leaderboard, along with the timer for the default season used by the others.
+func scheduleSeasons() {
+	log.Trace("--> leaderboard.scheduleSeasons")
+	defer log.Trace("<-- leaderboard.scheduleSeasons")
+
+	for _, lb := range getLeaderboards() {
+		scheduleSeason(lb)
+		scheduleSnapshots(lb)
+	}
+	scheduleDefaultSeason()
+}
+
+// scheduleDefaultSeason sets a timer for the end of the default season. A guild that has a bank but has
+// never used the leaderboard doesn't have a season of its own, so its monthly balances are reset when
+// the default season ends.
+func scheduleDefaultSeason() {
+	log.Trace("--> leaderboard.scheduleDefaultSeason")
+	defer log.Trace("<-- leaderboard.scheduleDefaultSeason")
+
+	lb := &Leaderboard{SeasonLength: DEFAULT_SEASON_LENGTH, Timezone: DEFAULT_TIMEZONE}
+	end := lb.nextSeason(lb.seasonStart(time.Now()))
+	time.AfterFunc(time.Until(end), endDefaultSeason)
+	log.WithFields(log.Fields{"end": end}).Debug("scheduled end of default season")
+}
+
+// endDefaultSeason resets the monthly balances for all guilds that don't have a leaderboard, and starts
+// the next default season.
+func endDefaultSeason() {
+	log.Trace("--> leaderboard.endDefaultSeason")
+	defer log.Trace("<-- leaderboard.endDefaultSeason")
+
+	defer scheduleDefaultSeason()
+
+	// Guilds with a leaderboard reset their balances when their own season ends
+	var leaderboards []*Leaderboard
+	err := db.FindMany(LEADERBOARD_COLLECTION, bson.D{}, &leaderboards, bson.D{}, 0)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("unable to get leaderboards, not resetting monthly balances")
+		return
+	}
+	guildIDs := make([]string, 0, len(leaderboards))
+	for _, lb := range leaderboards {
+		guildIDs = append(guildIDs, lb.GuildID)
+	}
+	bank.ResetMonthlyBalances(guildIDs...)
+	log.WithFields(log.Fields{"excluded": len(guildIDs)}).Info("reset monthly balances for guilds without a leaderboard")
+}
@@ -0,0 +1,65 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextSeason(t *testing.T) {
+	tests := []struct {
+		name     string
+		lb       *Leaderboard
+		start    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "default",
+			lb:       &Leaderboard{},
+			start:    time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly",
+			lb:       &Leaderboard{SeasonLength: SEASON_WEEKLY, RolloverHour: 6},
+			start:    time.Date(2024, time.March, 4, 6, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, time.March, 11, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "custom",
+			lb:       &Leaderboard{SeasonLength: SEASON_CUSTOM, SeasonDays: 14},
+			start:    time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "timezone",
+			lb:       &Leaderboard{SeasonLength: SEASON_MONTHLY, Timezone: "America/New_York", RolloverHour: 12},
+			start:    time.Date(2024, time.May, 1, 16, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, time.June, 1, 16, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := tc.lb.nextSeason(tc.start)
+			if !next.Equal(tc.expected) {
+				t.Errorf("expected the season to end at %s, got %s", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestSeasonStart(t *testing.T) {
+	lb := &Leaderboard{SeasonLength: SEASON_MONTHLY, RolloverHour: 6}
+	start := lb.seasonStart(time.Date(2024, time.March, 1, 5, 0, 0, 0, time.UTC))
+	expected := time.Date(2024, time.February, 1, 6, 0, 0, 0, time.UTC)
+	if !start.Equal(expected) {
+		t.Errorf("expected the season to start at %s, got %s", expected, start)
+	}
+
+	lb = &Leaderboard{SeasonLength: SEASON_WEEKLY, RolloverHour: 6}
+	start = lb.seasonStart(time.Date(2024, time.March, 1, 5, 0, 0, 0, time.UTC))
+	expected = time.Date(2024, time.February, 29, 6, 0, 0, 0, time.UTC)
+	if !start.Equal(expected) {
+		t.Errorf("expected the season to start at %s, got %s", expected, start)
+	}
+}
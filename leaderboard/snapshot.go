@@ -0,0 +1,170 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	snapshotTimers = make(map[string]*time.Timer)
+	snapshotLock   = sync.Mutex{}
+)
+
+// RankSnapshot is the rank of each member on a leaderboard at a point in time.
+type RankSnapshot struct {
+	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID string             `json:"guild_id" bson:"guild_id"`
+	Metric  string             `json:"metric" bson:"metric"`
+	Day     time.Time          `json:"day" bson:"day"` // Start of the day on which the snapshot was taken
+	Ranks   map[string]int     `json:"ranks" bson:"ranks"`
+}
+
+// getRank returns the member's rank in the snapshot, or 0 if the member wasn't ranked.
+func (snapshot *RankSnapshot) getRank(memberID string) int {
+	if snapshot == nil {
+		return 0
+	}
+	return snapshot.Ranks[memberID]
+}
+
+// today returns the start of the current day for the leaderboard, which begins at the rollover hour.
+func (lb *Leaderboard) today() time.Time {
+	now := time.Now().In(lb.getLocation())
+	year, month, day := now.Date()
+	start := lb.rolloverTime(year, month, day)
+	if start.After(now) {
+		start = lb.rolloverTime(year, month, day-1)
+	}
+	return start
+}
+
+// takeRankSnapshot saves the rank of every member on the leaderboard for the balance metric.
+func takeRankSnapshot(lb *Leaderboard, metric *balanceMetric, day time.Time) *RankSnapshot {
+	log.Trace("--> leaderboard.takeRankSnapshot")
+	defer log.Trace("<-- leaderboard.takeRankSnapshot")
+
+	filter := bson.D{{Key: "guild_id", Value: lb.GuildID}}
+	sort := bson.D{{Key: metric.Field, Value: -1}, {Key: "_id", Value: 1}}
+	accounts := bank.GetAccounts(lb.GuildID, filter, sort, 0)
+
+	snapshot := &RankSnapshot{
+		GuildID: lb.GuildID,
+		Metric:  metric.Name,
+		Day:     day,
+		Ranks:   make(map[string]int, len(accounts)),
+	}
+	for idx, account := range accounts {
+		snapshot.Ranks[account.MemberID] = idx + 1
+	}
+	writeRankSnapshot(snapshot)
+
+	return snapshot
+}
+
+// getPreviousSnapshot returns the most recent snapshot taken before today for the balance metric, or
+// `nil` if there isn't one. If a snapshot hasn't been taken today, one is taken so that it may be used
+// for comparison tomorrow.
+func getPreviousSnapshot(lb *Leaderboard, metric *balanceMetric) *RankSnapshot {
+	log.Trace("--> leaderboard.getPreviousSnapshot")
+	defer log.Trace("<-- leaderboard.getPreviousSnapshot")
+
+	today := lb.today()
+	latest := readRankSnapshot(lb.GuildID, metric.Name, today.Add(time.Second))
+	if latest == nil || !latest.Day.Equal(today) {
+		takeRankSnapshot(lb, metric, today)
+	}
+
+	return readRankSnapshot(lb.GuildID, metric.Name, today)
+}
+
+// getSeasonStartSnapshot returns the snapshot taken at the start of the current season for the balance
+// metric, or `nil` if there isn't one.
+func getSeasonStartSnapshot(lb *Leaderboard, metric *balanceMetric) *RankSnapshot {
+	log.Trace("--> leaderboard.getSeasonStartSnapshot")
+	defer log.Trace("<-- leaderboard.getSeasonStartSnapshot")
+
+	return readRankSnapshot(lb.GuildID, metric.Name, lb.LastSeason.Add(time.Second))
+}
+
+// takeRankSnapshots takes a snapshot of the ranks for each balance metric.
+func takeRankSnapshots(lb *Leaderboard, day time.Time) {
+	log.Trace("--> leaderboard.takeRankSnapshots")
+	defer log.Trace("<-- leaderboard.takeRankSnapshots")
+
+	for _, metric := range balanceMetrics {
+		takeRankSnapshot(lb, metric, day)
+	}
+}
+
+// takeDailySnapshots takes the daily snapshot of the ranks for the guild and removes the snapshots
+// that are no longer needed to compare against yesterday or the start of the season.
+func takeDailySnapshots(guildID string) {
+	log.Trace("--> leaderboard.takeDailySnapshots")
+	defer log.Trace("<-- leaderboard.takeDailySnapshots")
+
+	lb := readLeaderboard(guildID)
+	if lb == nil {
+		log.WithField("guild", guildID).Warn("leaderboard no longer exists, ending its snapshot schedule")
+		return
+	}
+
+	if !guild.IsPluginEnabled(guildID, PLUGIN_NAME) {
+		log.WithField("guild", guildID).Debug("leaderboard is disabled, skipping the daily rank snapshots")
+		scheduleSnapshots(lb)
+		return
+	}
+
+	today := lb.today()
+	takeRankSnapshots(lb, today)
+	cutoff := today.AddDate(0, 0, -1)
+	if lb.LastSeason.Before(cutoff) {
+		cutoff = lb.LastSeason
+	}
+	deleteRankSnapshots(lb.GuildID, cutoff)
+	log.WithFields(log.Fields{"guild": lb.GuildID, "day": today}).Info("took daily rank snapshots")
+
+	scheduleSnapshots(lb)
+}
+
+// scheduleSnapshots sets a timer to take the rank snapshots at the start of the next day for the
+// leaderboard, replacing any timer previously set for the guild.
+func scheduleSnapshots(lb *Leaderboard) {
+	log.Trace("--> leaderboard.scheduleSnapshots")
+	defer log.Trace("<-- leaderboard.scheduleSnapshots")
+
+	snapshotLock.Lock()
+	defer snapshotLock.Unlock()
+
+	if timer, ok := snapshotTimers[lb.GuildID]; ok {
+		timer.Stop()
+	}
+	guildID := lb.GuildID
+	year, month, day := lb.today().Date()
+	tomorrow := lb.rolloverTime(year, month, day+1)
+	snapshotTimers[guildID] = time.AfterFunc(time.Until(tomorrow), func() {
+		takeDailySnapshots(guildID)
+	})
+	log.WithFields(log.Fields{"guild": guildID, "next": tomorrow}).Debug("scheduled daily rank snapshots")
+}
+
+// formatMovement returns the number of places moved since the previous rank, with an arrow showing the
+// direction of the movement.
+func formatMovement(previousRank int, rank int) string {
+	switch {
+	case previousRank == 0:
+		return "n/a"
+	case previousRank > rank:
+		return fmt.Sprintf("▲%d", previousRank-rank)
+	case previousRank < rank:
+		return fmt.Sprintf("▼%d", rank-previousRank)
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/rbrabson/goblin/bank"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFormatMovement(t *testing.T) {
+	tests := []struct {
+		previous int
+		rank     int
+		expected string
+	}{
+		{0, 3, "n/a"},
+		{5, 3, "▲2"},
+		{3, 5, "▼2"},
+		{4, 4, "-"},
+	}
+
+	for _, tc := range tests {
+		if got := formatMovement(tc.previous, tc.rank); got != tc.expected {
+			t.Errorf("expected %q moving from %d to %d, got %q", tc.expected, tc.previous, tc.rank, got)
+		}
+	}
+}
+
+func TestSnapshotGetRank(t *testing.T) {
+	var missing *RankSnapshot
+	if rank := missing.getRank("1"); rank != 0 {
+		t.Errorf("expected no rank without a snapshot, got %d", rank)
+	}
+
+	snapshot := &RankSnapshot{Ranks: map[string]int{"1": 4}}
+	if rank := snapshot.getRank("1"); rank != 4 {
+		t.Errorf("expected a rank of 4, got %d", rank)
+	}
+	if rank := snapshot.getRank("2"); rank != 0 {
+		t.Errorf("expected no rank for an unranked member, got %d", rank)
+	}
+}
+
+func TestSnapshotMatchesPosition(t *testing.T) {
+	defer func() {
+		db.DeleteMany(RANK_SNAPSHOT_COLLECTION, bson.M{"guild_id": "12345"})
+		db.Delete(LEADERBOARD_COLLECTION, bson.M{"guild_id": "12345"})
+		db.Delete(bank.BANK_COLLECTION, bson.M{"guild_id": "12345"})
+		db.DeleteMany(bank.ACCOUNT_COLLECTION, bson.M{"guild_id": "12345"})
+	}()
+
+	// Members 1 and 2 are tied, so an unchanged leaderboard must show no movement for either
+	bank.SetDB(db)
+	bank.GetBank("12345")
+	for memberID, balance := range map[string]int{"1": 500, "2": 500, "3": 1000} {
+		bank.GetAccount("12345", memberID).SetBalance(balance)
+	}
+
+	lb := newLeaderboard("12345")
+	metric := getBalanceMetric("current")
+	snapshot := takeRankSnapshot(lb, metric, lb.today())
+	for _, memberID := range []string{"1", "2", "3"} {
+		position := lb.getPosition(metric, bank.GetAccount("12345", memberID))
+		if rank := snapshot.getRank(memberID); rank != position {
+			t.Errorf("expected member %s to have a snapshot rank of %d, got %d", memberID, position, rank)
+		}
+		if movement := formatMovement(snapshot.getRank(memberID), position); movement != "-" {
+			t.Errorf("expected no movement for member %s, got %q", memberID, movement)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/internal/discmsg"
 	"github.com/rbrabson/goblin/internal/format"
 	log "github.com/sirupsen/logrus"
@@ -12,16 +13,13 @@ import (
 )
 
 var (
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"payday": payday,
-	}
-
-	memberCommands = []*discordgo.ApplicationCommand{
-		{
+	memberRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "payday",
 			Description: "Deposits your daily check into your bank account.",
+			Handler:     payday,
 		},
-	}
+	)
 )
 
 // payday gives some credits to the player every 24 hours.
@@ -2,7 +2,6 @@ package payday
 
 import (
 	"fmt"
-	"slices"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -10,6 +9,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
 	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
 )
 
 const (
@@ -32,16 +32,7 @@ func Start() {
 
 // GetMemberHelp returns help information about the heist bot commands
 func GetMemberHelp() []string {
-	help := make([]string, 0, 1)
-
-	for _, command := range memberCommands {
-		commandDescription := fmt.Sprintf("- **/%s**:  %s\n", command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
-	help = append([]string{"**Payday**\n"}, help...)
-
-	return help
+	return append([]string{"**Payday**\n"}, memberRouter.Help()...)
 }
 
 // GetAdminHelp returns help information about the heist bot commands
@@ -56,19 +47,22 @@ func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 
 // GetCommands returns the commands for the banking system
 func (plugin *Plugin) GetCommands() []*discordgo.ApplicationCommand {
-	commands := make([]*discordgo.ApplicationCommand, 0, len(memberCommands))
-	commands = append(commands, memberCommands...)
-	return commands
+	return memberRouter.Commands()
+}
+
+// GetCommandPermissions returns the permission required to use each of the commands for the payday system
+func (plugin *Plugin) GetCommandPermissions() map[string]guild.Permission {
+	return memberRouter.Permissions(guild.PermissionMember)
 }
 
 // GetCommandHandlers returns the command handlers for the banking system
 func (plugin *Plugin) GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	return memberRouter.Handlers()
 }
 
 // GetComponentHandlers returns the component handlers for the banking system
 func (plugin *Plugin) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	return nil
 }
 
 // GetName returns the name of the banking system plugin
@@ -78,17 +72,8 @@ func (plugin *Plugin) GetName() string {
 
 // GetHelp returns the member help for the banking system
 func (plugin *Plugin) GetHelp() []string {
-	help := make([]string, 0, 1)
-
-	for _, command := range memberCommands {
-		commandDescription := fmt.Sprintf("- **/%s**: %s\n", command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, memberRouter.Help()...)
 }
 
 // GetAdminHelp returns the admin help for the banking system
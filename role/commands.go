@@ -5,54 +5,166 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
 	"github.com/rbrabson/goblin/internal/discmsg"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 var (
-	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"guild-admin": guildAdmin,
-	}
-
-	adminCommands = []*discordgo.ApplicationCommand{
-		{
+	adminRouter = discord.NewRouter(
+		&discord.Command{
 			Name:        "guild-admin",
 			Description: "Commands used to configure the bot for a given server.",
-			Options: []*discordgo.ApplicationCommandOption{
+			Subcommands: []*discord.Command{
+				{
+					Name:        "command",
+					Description: "Manages the roles allowed or denied the use of individual commands on this server.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "allow",
+							Description: "Allows members with a role to use a command.",
+							Handler:     requireOwner(allowCommand),
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "command",
+									Description: "The command, subcommand group or subcommand, such as \"heist-admin clear\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role the override applies to.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "deny",
+							Description: "Denies members with a role the use of a command.",
+							Handler:     requireOwner(denyCommand),
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "command",
+									Description: "The command, subcommand group or subcommand, such as \"heist-admin clear\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role the override applies to.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "reset",
+							Description: "Removes the override for a command and role.",
+							Handler:     requireOwner(resetCommandOverride),
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "command",
+									Description: "The command, subcommand group or subcommand, such as \"heist-admin clear\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role the override applies to.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "list",
+							Description: "Returns the command overrides for the server.",
+							Handler:     requireOwner(listCommandOverrides),
+						},
+					},
+				},
+				{
+					Name:        "plugin",
+					Description: "Enables or disables plugins on this server.",
+					Subcommands: []*discord.Command{
+						{
+							Name:        "enable",
+							Description: "Enables a plugin on this server.",
+							Handler:     enablePlugin,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "plugin",
+									Description: "The name of the plugin, such as \"heist\" or \"race\".",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "disable",
+							Description: "Disables a plugin on this server.",
+							Handler:     disablePlugin,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "plugin",
+									Description: "The name of the plugin, such as \"heist\" or \"race\".",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "list",
+							Description: "Returns the plugins and whether each is enabled on this server.",
+							Handler:     listPlugins,
+						},
+					},
+				},
 				{
 					Name:        "role",
-					Description: "Manages the admin roles for the bot for this server.",
-					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
-					Options: []*discordgo.ApplicationCommandOption{
+					Description: "Manages the roles given admin or moderator permissions for the bot on this server.",
+					Subcommands: []*discord.Command{
 						{
 							Name:        "list",
-							Description: "Returns the list of admin roles for the server.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Description: "Returns the list of admin and moderator roles for the server.",
+							Handler:     listRoles,
 						},
 						{
 							Name:        "add",
-							Description: "Adds an admin role for this server.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Description: "Gives a role admin or moderator permissions for this server.",
+							Handler:     addRole,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
-									Type:        discordgo.ApplicationCommandOptionString,
-									Name:        "name",
-									Description: "The name of the role to add.",
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role to add.",
 									Required:    true,
 								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "permission",
+									Description: "The permission given to members with the role. Defaults to admin.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Admin", Value: guild.PermissionAdmin.String()},
+										{Name: "Moderator", Value: guild.PermissionModerator.String()},
+									},
+								},
 							},
 						},
 						{
 							Name:        "remove",
-							Description: "Removes an admin role for this server.",
-							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Description: "Removes the admin or moderator permissions from a role for this server.",
+							Handler:     removeRole,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
-									Type:        discordgo.ApplicationCommandOptionString,
-									Name:        "name",
-									Description: "The name of the role to remove.",
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "The role to remove.",
 									Required:    true,
 								},
 							},
@@ -61,87 +173,68 @@ var (
 				},
 			},
 		},
-	}
+	)
 )
 
-// guildAdmin handles the guildAdmin command.
-func guildAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> server.guildAdmin")
-	defer log.Trace("<-- server.guildAdmin")
-
-	p := discmsg.GetPrinter(language.AmericanEnglish)
-
-	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
-		resp := p.Sprintf("You do not have permission to use this command.")
-		discmsg.SendEphemeralResponse(s, i, resp)
-		return
-	}
-
-	options := i.ApplicationCommandData().Options
-	if options[0].Name == "role" {
-		role(s, i)
-	} else {
-		log.WithFields(log.Fields{"command": options[0].Name}).Warn("unknown guild-admin command")
-	}
-}
-
-// role handles the role subcommands for the server command.
-func role(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	log.Trace("--> server.role")
-	defer log.Trace("<-- server.role")
-
-	options := i.ApplicationCommandData().Options[0].Options
-	switch options[0].Name {
-	case "add":
-		addRole(s, i)
-	case "list":
-		listRoles(s, i)
-	case "remove":
-		removeRole(s, i)
-	default:
-		log.WithFields(log.Fields{"subcommand": options[0].Name}).Warn("unknown guild-admin role command")
-	}
-}
-
-// addRole adds a role to the list of admin roles for the server.
+// addRole gives a role admin or moderator permissions for the server.
 func addRole(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> server.addRole")
 	defer log.Trace("<-- server.addRole")
 
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
 	guildID := i.GuildID
-	options := i.ApplicationCommandData().Options[0].Options[0].Options
-	roleName := options[0].StringValue()
+	var role *discordgo.Role
+	permission := guild.PermissionAdmin
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "role":
+			role = option.RoleValue(s, guildID)
+		case "permission":
+			permission, _ = guild.ParsePermission(option.StringValue())
+		}
+	}
 
 	// Get the server configuration
 	server := guild.GetGuild(guildID)
 
 	// Add the role to the server configuration
-	server.AddAdminRole(roleName)
-	log.WithFields(log.Fields{"guild": guildID, "role": roleName}).Debug("/guild-admin role add")
+	err := server.AddRole(role.ID, permission)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to add the role: %s.", err))
+		return
+	}
+	log.WithFields(log.Fields{"guild": guildID, "role": role.ID, "permission": permission}).Debug("/guild-admin role add")
 
-	discmsg.SendResponse(s, i, fmt.Sprintf("Role \"%s\" added", roleName))
+	discmsg.SendResponse(s, i, p.Sprintf("Role \"%s\" given the %s permission", role.Name, permission))
 }
 
-// removeRole removes a role from the list of admin roles for the server.
+// removeRole removes the admin or moderator permissions from a role for the server.
 func removeRole(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> server.removeRole")
 	defer log.Trace("<-- server.removeRole")
 
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
 	guildID := i.GuildID
-	options := i.ApplicationCommandData().Options[0].Options
-	roleName := options[0].StringValue()
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	role := options[0].RoleValue(s, guildID)
 
 	// Get the server configuration
 	server := guild.GetGuild(guildID)
 
 	// Remove the role from the server configuration
-	server.RemoveAdminRole(roleName)
-	log.WithFields(log.Fields{"guild": guildID, "role": roleName}).Debug("/guild-admin role remove")
+	err := server.RemoveRole(role.ID)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to remove the role: %s.", err))
+		return
+	}
+	log.WithFields(log.Fields{"guild": guildID, "role": role.ID}).Debug("/guild-admin role remove")
 
-	discmsg.SendResponse(s, i, fmt.Sprintf("Role \"%s\" removed", roleName))
+	discmsg.SendResponse(s, i, p.Sprintf("Role \"%s\" removed", role.Name))
 }
 
-// listRoles lists the admin roles for the server.
+// listRoles lists the admin and moderator roles for the server.
 func listRoles(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Trace("--> server.listRoles")
 	defer log.Trace("<-- server.listRoles")
@@ -151,17 +244,212 @@ func listRoles(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Get the server configuration
 	server := guild.GetGuild(guildID)
 
-	// Get the list of admin roles
-	roles := server.GetAdminRoles()
-
-	// Send the list of admin roles to the user
+	// Send the list of roles to the user
+	guildRoles := guild.GetGuildRoles(s, guildID)
 	var sb strings.Builder
-	sb.WriteString("**Admin Roles**:\n")
-	for _, role := range roles {
-		sb.WriteString(role + "\n")
+	for _, permission := range []guild.Permission{guild.PermissionAdmin, guild.PermissionModerator} {
+		sb.WriteString(fmt.Sprintf("**%s Roles**:\n", cases.Title(language.AmericanEnglish).String(permission.String())))
+		roles := server.GetRoles(permission)
+		if len(roles) == 0 {
+			sb.WriteString("None\n")
+		}
+		for _, roleID := range roles {
+			roleName := roleID
+			for _, role := range guildRoles {
+				if role.ID == roleID {
+					roleName = role.Name
+				}
+			}
+			sb.WriteString(roleName + "\n")
+		}
 	}
+	sb.WriteString("\nThe server owner and members with the Administrator permission are always admins.")
 	roleList := sb.String()
 	log.WithFields(log.Fields{"guild": guildID, "roles": roleList}).Debug("/guild-admin role list")
 
 	discmsg.SendEphemeralResponse(s, i, roleList)
 }
+
+// requireOwner only lets the owner of the server use the command. This stops admins from lifting
+// restrictions that the owner placed on them.
+func requireOwner(next discord.HandlerFunc) discord.HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if !guild.IsOwner(s, i.GuildID, i.Member.User.ID) {
+			p := discmsg.GetPrinter(language.AmericanEnglish)
+			discmsg.SendEphemeralResponse(s, i, p.Sprintf("Only the server owner may change command permissions."))
+			return
+		}
+		next(s, i)
+	}
+}
+
+// getCommandOverrideOptions returns the command and role passed to a command override subcommand.
+func getCommandOverrideOptions(s *discordgo.Session, i *discordgo.InteractionCreate) (string, *discordgo.Role) {
+	var commandPath string
+	var role *discordgo.Role
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "command":
+			commandPath = guild.NormalizeCommand(option.StringValue())
+		case "role":
+			role = option.RoleValue(s, i.GuildID)
+		}
+	}
+	return commandPath, role
+}
+
+// allowCommand allows members with a role to use a command.
+func allowCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	setCommandOverride(s, i, true)
+}
+
+// denyCommand denies members with a role the use of a command.
+func denyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	setCommandOverride(s, i, false)
+}
+
+// setCommandOverride allows or denies members with a role the use of a command.
+func setCommandOverride(s *discordgo.Session, i *discordgo.InteractionCreate, allow bool) {
+	log.Trace("--> server.setCommandOverride")
+	defer log.Trace("<-- server.setCommandOverride")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	commandPath, role := getCommandOverrideOptions(s, i)
+	if !discord.IsCommand(commandPath) {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("\"/%s\" is not a command.", commandPath))
+		return
+	}
+
+	server := guild.GetGuild(i.GuildID)
+	server.SetCommandOverride(commandPath, role.ID, allow)
+	log.WithFields(log.Fields{"guild": i.GuildID, "command": commandPath, "role": role.ID, "allow": allow}).Debug("/guild-admin command")
+
+	if allow {
+		discmsg.SendResponse(s, i, p.Sprintf("Role \"%s\" allowed to use \"/%s\"", role.Name, commandPath))
+	} else {
+		discmsg.SendResponse(s, i, p.Sprintf("Role \"%s\" denied the use of \"/%s\"", role.Name, commandPath))
+	}
+}
+
+// resetCommandOverride removes the override for a command and role.
+func resetCommandOverride(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> server.resetCommandOverride")
+	defer log.Trace("<-- server.resetCommandOverride")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	commandPath, role := getCommandOverrideOptions(s, i)
+	server := guild.GetGuild(i.GuildID)
+	err := server.RemoveCommandOverride(commandPath, role.ID)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to reset the command: %s.", err))
+		return
+	}
+	log.WithFields(log.Fields{"guild": i.GuildID, "command": commandPath, "role": role.ID}).Debug("/guild-admin command reset")
+
+	discmsg.SendResponse(s, i, p.Sprintf("Override for role \"%s\" removed from \"/%s\"", role.Name, commandPath))
+}
+
+// listCommandOverrides lists the command overrides for the server.
+func listCommandOverrides(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> server.listCommandOverrides")
+	defer log.Trace("<-- server.listCommandOverrides")
+
+	server := guild.GetGuild(i.GuildID)
+	if len(server.CommandOverrides) == 0 {
+		discmsg.SendEphemeralResponse(s, i, "No command overrides are set for this server.")
+		return
+	}
+
+	guildRoles := guild.GetGuildRoles(s, i.GuildID)
+	var sb strings.Builder
+	sb.WriteString("**Command Overrides**:\n")
+	for _, override := range server.CommandOverrides {
+		roleName := override.RoleID
+		for _, role := range guildRoles {
+			if role.ID == override.RoleID {
+				roleName = role.Name
+			}
+		}
+		action := "deny"
+		if override.Allow {
+			action = "allow"
+		}
+		sb.WriteString(fmt.Sprintf("/%s: %s %s\n", override.Command, action, roleName))
+	}
+
+	discmsg.SendEphemeralResponse(s, i, sb.String())
+}
+
+// enablePlugin enables a plugin for the server.
+func enablePlugin(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> server.enablePlugin")
+	defer log.Trace("<-- server.enablePlugin")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	name := strings.ToLower(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	if discord.GetPlugin(name) == nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("\"%s\" is not a plugin.", name))
+		return
+	}
+
+	server := guild.GetGuild(i.GuildID)
+	err := server.EnablePlugin(name)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to enable the plugin: %s.", err))
+		return
+	}
+	log.WithFields(log.Fields{"guild": i.GuildID, "plugin": name}).Debug("/guild-admin plugin enable")
+
+	discmsg.SendResponse(s, i, p.Sprintf("Plugin \"%s\" enabled", name))
+}
+
+// disablePlugin disables a plugin for the server.
+func disablePlugin(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> server.disablePlugin")
+	defer log.Trace("<-- server.disablePlugin")
+
+	p := discmsg.GetPrinter(language.AmericanEnglish)
+
+	name := strings.ToLower(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	if discord.GetPlugin(name) == nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("\"%s\" is not a plugin.", name))
+		return
+	}
+	if name == PLUGIN_NAME {
+		// Disabling this plugin would prevent any plugin from being enabled again
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("The %s plugin can't be disabled.", name))
+		return
+	}
+
+	server := guild.GetGuild(i.GuildID)
+	err := server.DisablePlugin(name)
+	if err != nil {
+		discmsg.SendEphemeralResponse(s, i, p.Sprintf("Unable to disable the plugin: %s.", err))
+		return
+	}
+	log.WithFields(log.Fields{"guild": i.GuildID, "plugin": name}).Debug("/guild-admin plugin disable")
+
+	discmsg.SendResponse(s, i, p.Sprintf("Plugin \"%s\" disabled", name))
+}
+
+// listPlugins lists the plugins and whether each is enabled for the server.
+func listPlugins(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Trace("--> server.listPlugins")
+	defer log.Trace("<-- server.listPlugins")
+
+	server := guild.GetGuild(i.GuildID)
+	var sb strings.Builder
+	sb.WriteString("**Plugins**:\n")
+	for _, registered := range discord.ListPlugin() {
+		status := "enabled"
+		if !server.IsPluginEnabled(registered.GetName()) {
+			status = "disabled"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", registered.GetName(), status))
+	}
+
+	discmsg.SendEphemeralResponse(s, i, sb.String())
+}
@@ -2,11 +2,11 @@ package role
 
 import (
 	"fmt"
-	"slices"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/rbrabson/goblin/database/mongo"
 	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -36,19 +36,22 @@ func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 
 // GetCommands returns the commands for the banking system
 func (plugin *Plugin) GetCommands() []*discordgo.ApplicationCommand {
-	commands := make([]*discordgo.ApplicationCommand, 0, len(adminCommands))
-	commands = append(commands, adminCommands...)
-	return commands
+	return adminRouter.Commands()
+}
+
+// GetCommandPermissions returns the permission required to use each of the commands for the guild roles
+func (plugin *Plugin) GetCommandPermissions() map[string]guild.Permission {
+	return adminRouter.Permissions(guild.PermissionAdmin)
 }
 
 // GetCommandHandlers returns the command handlers for the banking system
 func (plugin *Plugin) GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	return adminRouter.Handlers()
 }
 
 // GetComponentHandlers returns the component handlers for the banking system
 func (plugin *Plugin) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return commandHandlers
+	return nil
 }
 
 // GetName returns the name of the banking system plugin
@@ -63,16 +66,6 @@ func (plugin *Plugin) GetHelp() []string {
 
 // GetAdminHelp returns the admin help for the banking system
 func (plugin *Plugin) GetAdminHelp() []string {
-	help := make([]string, 0, len(adminCommands[0].Options))
-
-	commandPrefix := adminCommands[0].Name
-	for _, command := range adminCommands[0].Options {
-		commandDescription := fmt.Sprintf("- **/%s %s**:  %s\n", commandPrefix, command.Name, command.Description)
-		help = append(help, commandDescription)
-	}
-	slices.Sort(help)
 	title := fmt.Sprintf("**%s**\n", cases.Title(language.AmericanEnglish, cases.Compact).String(PLUGIN_NAME))
-	help = append([]string{title}, help...)
-
-	return help
+	return append([]string{title}, adminRouter.Help()...)
 }
@@ -37,10 +37,8 @@ func TestGetServer(t *testing.T) {
 	}
 	servers = append(servers, server)
 
-	for i, role := range server.AdminRoles {
-		if !slices.Contains(guild.DEFAULT_ADMIN_ROLES, role) {
-			t.Errorf("Expected role to be %s, got %s", guild.DEFAULT_ADMIN_ROLES[i], role)
-		}
+	if len(server.AdminRoleIDs) != 0 || len(server.ModeratorRoleIDs) != 0 {
+		t.Errorf("Expected no roles for a new server, got %v and %v", server.AdminRoleIDs, server.ModeratorRoleIDs)
 	}
 }
 
@@ -60,15 +58,15 @@ func TestAddAdminRole(t *testing.T) {
 	}
 	servers = append(servers, server)
 
-	server.AddAdminRole("NewRole")
+	server.AddRole("67890", guild.PermissionAdmin)
 	server = guild.GetGuild(server.GuildID)
 	if server == nil {
 		t.Errorf("Expected server to be retrieved")
 		return
 	}
 
-	if !slices.Contains(server.AdminRoles, "NewRole") { // This test will fail.
-		t.Errorf("Expected role %s to be in the list of admin roles", "NewRole")
+	if !slices.Contains(server.AdminRoleIDs, "67890") {
+		t.Errorf("Expected role %s to be in the list of admin roles", "67890")
 	}
 }
 
@@ -88,14 +86,15 @@ func TestRemoveAdminRole(t *testing.T) {
 	}
 	servers = append(servers, server)
 
-	server.RemoveAdminRole("NewRole")
+	server.AddRole("67890", guild.PermissionModerator)
+	server.RemoveRole("67890")
 	server = guild.GetGuild(server.GuildID)
 	if server == nil {
 		t.Errorf("Expected server to be retrieved")
 		return
 	}
-	if slices.Contains(server.AdminRoles, "NewRole") { // This test will fail.
-		t.Errorf("Expected role %s to not be in the list of admin roles", "NewRole")
+	if slices.Contains(server.AdminRoleIDs, "67890") || slices.Contains(server.ModeratorRoleIDs, "67890") {
+		t.Errorf("Expected role %s to not be in the list of roles", "67890")
 	}
 }
 
@@ -115,10 +114,12 @@ func TestListAdminRoles(t *testing.T) {
 	}
 	servers = append(servers, server)
 
-	roles := server.GetAdminRoles()
-	for i, role := range roles {
-		if !slices.Contains(guild.DEFAULT_ADMIN_ROLES, role) {
-			t.Errorf("Expected role to not be %s, got %s", guild.DEFAULT_ADMIN_ROLES[i], role)
-		}
+	server.AddRole("67890", guild.PermissionModerator)
+	roles := server.GetRoles(guild.PermissionModerator)
+	if !slices.Contains(roles, "67890") {
+		t.Errorf("Expected role %s to be in the list of moderator roles, got %v", "67890", roles)
+	}
+	if roles := server.GetAdminRoles(); slices.Contains(roles, "67890") {
+		t.Errorf("Expected role %s to not be in the list of admin roles, got %v", "67890", roles)
 	}
 }